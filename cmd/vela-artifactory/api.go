@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/sirupsen/logrus"
+)
+
+// apiGet sends a GET request to the path, relative to the Artifactory
+// instance URL, using the credentials configured for the client.
+func apiGet(cli artifactory.ArtifactoryServicesManager, path string) (*http.Response, []byte, error) {
+	return apiGetWithHeaders(cli, path, nil)
+}
+
+// apiGetWithHeaders sends a GET request with the headers to the path, relative
+// to the Artifactory instance URL, using the credentials configured for the client.
+func apiGetWithHeaders(cli artifactory.ArtifactoryServicesManager, path string, headers map[string]string) (*http.Response, []byte, error) {
+	details := cli.GetConfig().GetServiceDetails()
+	httpDetails := details.CreateHttpClientDetails()
+
+	if httpDetails.Headers == nil {
+		httpDetails.Headers = make(map[string]string)
+	}
+
+	for key, value := range headers {
+		httpDetails.Headers[key] = value
+	}
+
+	u := details.GetUrl() + strings.TrimPrefix(path, "/")
+
+	logrus.Tracef("sending GET request to %s", u)
+
+	resp, body, _, err := cli.Client().SendGet(u, true, &httpDetails)
+
+	return resp, body, err
+}
+
+// apiPost sends a POST request with the content to the path, relative to
+// the Artifactory instance URL, using the credentials configured for the client.
+func apiPost(cli artifactory.ArtifactoryServicesManager, path string, content []byte) (*http.Response, []byte, error) {
+	details := cli.GetConfig().GetServiceDetails()
+	httpDetails := details.CreateHttpClientDetails()
+
+	u := details.GetUrl() + strings.TrimPrefix(path, "/")
+
+	logrus.Tracef("sending POST request to %s", u)
+
+	return cli.Client().SendPost(u, content, &httpDetails)
+}
+
+// apiPut sends a PUT request to the path, relative to the Artifactory
+// instance URL, using the credentials configured for the client.
+func apiPut(cli artifactory.ArtifactoryServicesManager, path string) (*http.Response, []byte, error) {
+	details := cli.GetConfig().GetServiceDetails()
+	httpDetails := details.CreateHttpClientDetails()
+
+	u := details.GetUrl() + strings.TrimPrefix(path, "/")
+
+	logrus.Tracef("sending PUT request to %s", u)
+
+	return cli.Client().SendPut(u, nil, &httpDetails)
+}
+
+// apiDelete sends a DELETE request to the path, relative to the Artifactory
+// instance URL, using the credentials configured for the client.
+func apiDelete(cli artifactory.ArtifactoryServicesManager, path string) (*http.Response, []byte, error) {
+	details := cli.GetConfig().GetServiceDetails()
+	httpDetails := details.CreateHttpClientDetails()
+
+	u := details.GetUrl() + strings.TrimPrefix(path, "/")
+
+	logrus.Tracef("sending DELETE request to %s", u)
+
+	return cli.Client().SendDelete(u, nil, &httpDetails)
+}
+
+// repoFromPath returns the repository key from a path to artifact(s).
+func repoFromPath(path string) string {
+	repo, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	return repo
+}
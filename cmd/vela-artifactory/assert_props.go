@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const assertPropsAction = "assert-props"
+
+// PropAssertion represents the plugin configuration for asserting
+// the properties of artifact(s) before an action operates on them.
+type PropAssertion struct {
+	// RequiredProps are properties the artifact(s) must have
+	RequiredProps string
+	// ForbiddenProps are properties the artifact(s) must not have
+	ForbiddenProps string
+}
+
+// Enabled returns true when required or forbidden properties are provided.
+func (a *PropAssertion) Enabled() bool {
+	return len(a.RequiredProps) > 0 || len(a.ForbiddenProps) > 0
+}
+
+// propViolations returns the required properties the artifact is
+// missing and the forbidden properties the artifact has.
+func propViolations(item *utils.ResultItem, required, forbidden map[string][]string) []string {
+	current := make(map[string][]string)
+
+	for _, prop := range item.Properties {
+		current[prop.Key] = append(current[prop.Key], prop.Value)
+	}
+
+	var violations []string
+
+	// verify every required value is present on the artifact
+	for _, key := range sortedKeys(required) {
+		for _, pattern := range required[key] {
+			if !slices.ContainsFunc(current[key], matchPropValue(pattern)) {
+				violations = append(violations, fmt.Sprintf("missing %s=%s", key, pattern))
+			}
+		}
+	}
+
+	// verify no forbidden value is present on the artifact
+	for _, key := range sortedKeys(forbidden) {
+		for _, pattern := range forbidden[key] {
+			for _, value := range current[key] {
+				if matchPropValue(pattern)(value) {
+					violations = append(violations, fmt.Sprintf("forbidden %s=%s", key, value))
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+// Assert searches for the artifact(s) matching the parameters and
+// verifies each of them has the required and none of the forbidden
+// properties, logging a report with the outcome for every artifact.
+func (a *PropAssertion) Assert(
+	cli artifactory.ArtifactoryServicesManager,
+	logger *logrus.Entry,
+	params ...*utils.CommonParams,
+) error {
+	start := time.Now()
+
+	required, err := parsePropPatterns(a.RequiredProps)
+	if err != nil {
+		return err
+	}
+
+	forbidden, err := parsePropPatterns(a.ForbiddenProps)
+	if err != nil {
+		return err
+	}
+
+	var total, failed int
+
+	logger.Infof("Asserting properties on artifact(s) (required: [%s], forbidden: [%s])", a.RequiredProps, a.ForbiddenProps)
+
+	for _, param := range params {
+		// create new search parameters
+		p := services.NewSearchParams()
+		p.CommonParams = param
+
+		// send API call to search for the artifact(s) in Artifactory
+		reader, err := cli.SearchFiles(p)
+		if err != nil {
+			return err
+		}
+
+		for item := new(utils.ResultItem); reader.NextRecord(item) == nil; item = new(utils.ResultItem) {
+			total++
+
+			violations := propViolations(item, required, forbidden)
+
+			entry := logger.WithField("artifact", item.GetItemRelativePath())
+
+			if len(violations) > 0 {
+				entry.Errorf("  [failed] %s: %s", item.GetItemRelativePath(), strings.Join(violations, ", "))
+
+				failed++
+
+				continue
+			}
+
+			entry.Infof("  [passed] %s", item.GetItemRelativePath())
+		}
+
+		err = reader.GetError()
+
+		reader.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// verify artifact(s) were found to assert the properties on
+	if total == 0 {
+		return fmt.Errorf("no artifact(s) found to assert properties")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d artifact(s) failed property assertions", failed, total)
+	}
+
+	withDuration(logger, start).WithField("success", total).Infof("All %d artifact(s) passed property assertions", total)
+
+	return nil
+}
+
+// Validate verifies the PropAssertion is properly configured.
+func (a *PropAssertion) Validate() error {
+	// verify the required properties are valid
+	_, err := parsePropPatterns(a.RequiredProps)
+	if err != nil {
+		return fmt.Errorf("invalid required_props provided: %w", err)
+	}
+
+	// verify the forbidden properties are valid
+	_, err = parsePropPatterns(a.ForbiddenProps)
+	if err != nil {
+		return fmt.Errorf("invalid forbidden_props provided: %w", err)
+	}
+
+	return nil
+}
+
+// parsePropPatterns parses the properties into the value patterns for each property.
+func parsePropPatterns(props string) (map[string][]string, error) {
+	if len(props) == 0 {
+		return nil, nil
+	}
+
+	parsed, err := utils.ParseProperties(props)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := parsed.ToMap()
+
+	for key, values := range patterns {
+		for _, value := range values {
+			// verify the value is a valid pattern
+			_, err := path.Match(value, "")
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %s for property %s: %w", value, key, err)
+			}
+		}
+	}
+
+	return patterns, nil
+}
+
+// matchPropValue returns a function reporting whether a value matches the pattern.
+func matchPropValue(pattern string) func(string) bool {
+	return func(value string) bool {
+		matched, _ := path.Match(pattern, value)
+
+		return matched
+	}
+}
+
+// sortedKeys returns the sorted keys of the properties.
+func sortedKeys(props map[string][]string) []string {
+	keys := make([]string, 0, len(props))
+
+	for key := range props {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// AssertProps represents the plugin configuration for asserting properties on artifacts.
+type AssertProps struct {
+	Selection
+
+	// Assert are the properties the artifact(s) must or must not have
+	Assert PropAssertion
+	// Path is the path to artifact(s) to assert properties on
+	Path string
+	// Recursive is a flag that enables asserting properties on sub-directories for the artifact(s) in the path
+	Recursive bool
+}
+
+// Exec formats and runs the commands for asserting properties on artifacts in Artifactory.
+func (a *AssertProps) Exec(cli artifactory.ArtifactoryServicesManager) error {
+	logrus.Trace("running assert-props with provided configuration")
+
+	logger := actionLogger(assertPropsAction, a.Path)
+
+	// verify the properties of the artifact(s) matching the path
+	return a.Assert.Assert(cli, logger, a.CommonParams(a.Path, a.Recursive))
+}
+
+// Validate verifies the AssertProps is properly configured.
+func (a *AssertProps) Validate() error {
+	logrus.Trace("validating assert-props plugin configuration")
+
+	// verify the selection is valid
+	err := a.Selection.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid assert-props selection provided: %w", err)
+	}
+
+	// verify path or aql is provided
+	err = a.validatePath(assertPropsAction, a.Path)
+	if err != nil {
+		return err
+	}
+
+	// verify required or forbidden properties are provided
+	if !a.Assert.Enabled() {
+		return fmt.Errorf("no assert-props required_props or forbidden_props provided")
+	}
+
+	// verify the properties are valid
+	err = a.Assert.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid assert-props assertion provided: %w", err)
+	}
+
+	return nil
+}
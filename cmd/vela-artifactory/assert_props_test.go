@@ -0,0 +1,428 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+// requestServer creates a mock server recording the method and path of every request.
+func requestServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		handler.ServeHTTP(w, r)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests
+	}
+}
+
+func TestArtifactory_propViolations(t *testing.T) {
+	// setup types
+	item := &utils.ResultItem{
+		Repo: "props-local",
+		Path: "app",
+		Name: "app.tar.gz",
+		Properties: []utils.Property{
+			{Key: "os", Value: "linux"},
+			{Key: "os", Value: "darwin"},
+			{Key: "qa.status", Value: "failed"},
+			{Key: "scan.severity", Value: "critical"},
+		},
+	}
+
+	// setup tests
+	tests := []struct {
+		name      string
+		required  string
+		forbidden string
+		want      []string
+	}{
+		{
+			name:     "required",
+			required: "os=linux,darwin;qa.status=*",
+		},
+		{
+			name:     "missing",
+			required: "qa.status=passed;scan.id=*;os=windows",
+			want:     []string{"missing os=windows", "missing qa.status=passed", "missing scan.id=*"},
+		},
+		{
+			name:      "forbidden",
+			forbidden: "qa.status=failed;scan.severity=crit*",
+			want:      []string{"forbidden qa.status=failed", "forbidden scan.severity=critical"},
+		},
+		{
+			name:      "not forbidden",
+			forbidden: "owner=*;os=windows",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			required, err := parsePropPatterns(test.required)
+			if err != nil {
+				t.Fatalf("parsePropPatterns returned err: %v", err)
+			}
+
+			forbidden, err := parsePropPatterns(test.forbidden)
+			if err != nil {
+				t.Fatalf("parsePropPatterns returned err: %v", err)
+			}
+
+			got := propViolations(item, required, forbidden)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("propViolations is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_AssertProps_Exec(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		assert  PropAssertion
+		wantErr string
+	}{
+		{
+			name:   "passed",
+			assert: PropAssertion{ForbiddenProps: "owner=root;qa.status=pending"},
+		},
+		{
+			name:    "required",
+			assert:  PropAssertion{RequiredProps: "qa.status=passed"},
+			wantErr: "2 of 3 artifact(s) failed property assertions",
+		},
+		{
+			name:    "forbidden",
+			assert:  PropAssertion{ForbiddenProps: "qa.status=failed"},
+			wantErr: "1 of 3 artifact(s) failed property assertions",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, _ := requestServer(t)
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "assert-props",
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				AssertProps: &AssertProps{
+					Path:   "props-local/app/*",
+					Assert: test.assert,
+				},
+			}
+
+			err := p.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = p.Exec()
+
+			if len(test.wantErr) == 0 && err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			if len(test.wantErr) > 0 && (err == nil || err.Error() != test.wantErr) {
+				t.Errorf("Exec returned err %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestArtifactory_AssertProps_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		assert  *AssertProps
+		wantErr bool
+	}{
+		{
+			name:   "required",
+			assert: &AssertProps{Path: "foo/bar", Assert: PropAssertion{RequiredProps: "qa.status=passed"}},
+		},
+		{
+			name:   "forbidden",
+			assert: &AssertProps{Path: "foo/bar", Assert: PropAssertion{ForbiddenProps: "qa.status=failed"}},
+		},
+		{
+			name:    "no path",
+			assert:  &AssertProps{Assert: PropAssertion{RequiredProps: "qa.status=passed"}},
+			wantErr: true,
+		},
+		{
+			name:    "no props",
+			assert:  &AssertProps{Path: "foo/bar"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid props",
+			assert:  &AssertProps{Path: "foo/bar", Assert: PropAssertion{RequiredProps: "qa.status"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			assert:  &AssertProps{Path: "foo/bar", Assert: PropAssertion{ForbiddenProps: "qa.status=[passed"}},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.assert.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Copy_Exec_Assert(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		assert   PropAssertion
+		wantCopy bool
+	}{
+		{
+			name:     "passed",
+			assert:   PropAssertion{ForbiddenProps: "owner=root"},
+			wantCopy: true,
+		},
+		{
+			name:   "failed",
+			assert: PropAssertion{RequiredProps: "qa.status=passed"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, requests := requestServer(t)
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "copy",
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				Copy: &Copy{
+					Path:   "props-local/app/*",
+					Target: "props-release/app/",
+					Assert: test.assert,
+				},
+			}
+
+			err := p.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = p.Exec()
+
+			if test.wantCopy && err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			if !test.wantCopy && err == nil {
+				t.Errorf("Exec should have returned err")
+			}
+
+			copied := slices.ContainsFunc(requests(), func(r string) bool {
+				return strings.HasPrefix(r, "POST /api/copy")
+			})
+
+			if copied != test.wantCopy {
+				t.Errorf("Exec copied artifacts is %v, want %v", copied, test.wantCopy)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Move_Exec_Assert(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		assert   PropAssertion
+		wantMove bool
+	}{
+		{
+			name:     "passed",
+			assert:   PropAssertion{ForbiddenProps: "owner=root"},
+			wantMove: true,
+		},
+		{
+			name:   "failed",
+			assert: PropAssertion{RequiredProps: "qa.status=passed"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, requests := requestServer(t)
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "move",
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				Move: &Move{
+					Path:   "props-local/app/*",
+					Target: "props-release/app/",
+					Assert: test.assert,
+				},
+			}
+
+			err := p.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = p.Exec()
+
+			if test.wantMove && err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			if !test.wantMove && err == nil {
+				t.Errorf("Exec should have returned err")
+			}
+
+			moved := slices.ContainsFunc(requests(), func(r string) bool {
+				return strings.HasPrefix(r, "POST /api/move")
+			})
+
+			if moved != test.wantMove {
+				t.Errorf("Exec moved artifacts is %v, want %v", moved, test.wantMove)
+			}
+		})
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_Assert(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name        string
+		assert      PropAssertion
+		wantPromote bool
+	}{
+		{
+			name:        "passed",
+			assert:      PropAssertion{ForbiddenProps: "qa.status=failed"},
+			wantPromote: true,
+		},
+		{
+			name:   "failed",
+			assert: PropAssertion{RequiredProps: "docker.manifest=*"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, requests := requestServer(t)
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "docker-promote",
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				DockerPromote: &DockerPromote{
+					TargetRepo:     "docker",
+					DockerRegistry: "github/octocat",
+					SourceTag:      "0.1.0",
+					TargetTags:     []string{"latest"},
+					Assert:         test.assert,
+				},
+			}
+
+			err := p.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = p.Exec()
+
+			if test.wantPromote && err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			if !test.wantPromote && err == nil {
+				t.Errorf("Exec should have returned err")
+			}
+
+			promoted := slices.ContainsFunc(requests(), func(r string) bool {
+				return strings.HasSuffix(r, "/promote")
+			})
+
+			if promoted != test.wantPromote {
+				t.Errorf("Exec promoted image is %v, want %v", promoted, test.wantPromote)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	jfrogauth "github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// authMethodBasic authenticates with a username and password.
+	authMethodBasic = "basic"
+	// authMethodAPIKey authenticates with an API key.
+	authMethodAPIKey = "api_key"
+	// authMethodAccessToken authenticates with an Access/Identity token.
+	authMethodAccessToken = "access_token"
+	// authMethodOIDC authenticates with an access token exchanged for an OIDC ID token.
+	authMethodOIDC = "oidc"
+	// authMethodClientCert authenticates with a TLS client certificate.
+	authMethodClientCert = "client_cert"
+
+	// oidcTokenPath is the path, relative to the JFrog platform URL,
+	// used for exchanging an OIDC ID token for an access token.
+	oidcTokenPath = "access/api/v1/oidc/token"
+)
+
+var (
+	// ErrInvalidAuthMethod defines the error type when the
+	// AuthMethod provided to the Config is unsupported.
+	ErrInvalidAuthMethod = errors.New("invalid auth method provided")
+
+	// ErrNoCredentials defines the error type when no credentials
+	// are provided to the Config for any supported auth method.
+	ErrNoCredentials = errors.New("no config credentials provided")
+)
+
+// authMethods represents the supported auth methods in order of precedence
+// when the auth method is not explicitly provided.
+var authMethods = []string{
+	authMethodAccessToken,
+	authMethodAPIKey,
+	authMethodBasic,
+	authMethodOIDC,
+	authMethodClientCert,
+}
+
+// ResolveAuthMethod returns the auth method used for communication
+// with the Artifactory instance. When an auth method is not explicitly
+// provided, the first method in order of precedence with credentials
+// provided is returned:
+//
+//	access_token > api_key > basic > oidc > client_cert
+//
+// The OIDC ID token is provided by Vela to every step, so OIDC is ranked
+// below the credentials the pipeline configures for the plugin itself.
+func (c *Config) ResolveAuthMethod() (string, error) {
+	// check if an auth method is explicitly provided
+	if len(c.AuthMethod) > 0 {
+		method := strings.ToLower(strings.TrimSpace(c.AuthMethod))
+
+		for _, m := range authMethods {
+			if method == m {
+				return m, nil
+			}
+		}
+
+		return "", fmt.Errorf(
+			"%w: %s (Valid auth methods: %s)",
+			ErrInvalidAuthMethod,
+			c.AuthMethod,
+			strings.Join(authMethods, ", "),
+		)
+	}
+
+	switch {
+	case len(c.Token) > 0:
+		return authMethodAccessToken, nil
+	case len(c.APIKey) > 0:
+		return authMethodAPIKey, nil
+	case len(c.Username) > 0 && len(c.Password) > 0:
+		return authMethodBasic, nil
+	case len(c.OIDCToken) > 0:
+		return authMethodOIDC, nil
+	case c.Client != nil && len(c.CertPath) > 0 && len(c.CertKeyPath) > 0:
+		return authMethodClientCert, nil
+	}
+
+	return "", ErrNoCredentials
+}
+
+// validateAuth verifies the credentials required for the auth method are provided.
+func (c *Config) validateAuth(method string) error {
+	logrus.Tracef("validating config credentials for %s auth method", method)
+
+	switch method {
+	case authMethodBasic:
+		// verify username is provided
+		if len(c.Username) == 0 {
+			return fmt.Errorf("no config username provided for %s auth method", method)
+		}
+
+		// verify password is provided
+		if len(c.Password) == 0 {
+			return fmt.Errorf("no config password provided for %s auth method", method)
+		}
+	case authMethodAPIKey:
+		// verify API key is provided
+		if len(c.APIKey) == 0 {
+			return fmt.Errorf("no config api-key provided for %s auth method", method)
+		}
+	case authMethodAccessToken:
+		// verify token is provided
+		if len(c.Token) == 0 {
+			return fmt.Errorf("no config token provided for %s auth method", method)
+		}
+	case authMethodOIDC:
+		// verify OIDC token is provided
+		if len(c.OIDCToken) == 0 {
+			return fmt.Errorf("no config oidc-token provided for %s auth method", method)
+		}
+
+		// verify OIDC provider is provided
+		if len(c.OIDCProvider) == 0 {
+			return fmt.Errorf("no config oidc-provider provided for %s auth method", method)
+		}
+	case authMethodClientCert:
+		// verify certificate and key are provided
+		if c.Client == nil || len(c.CertPath) == 0 || len(c.CertKeyPath) == 0 {
+			return fmt.Errorf("no config client cert or cert key provided for %s auth method", method)
+		}
+	}
+
+	return nil
+}
+
+// setAuth applies the credentials for the auth method to the Artifactory details.
+func (c *Config) setAuth(details jfrogauth.ServiceDetails, client *http.Client, method string) error {
+	logrus.Debugf("authenticating with the Artifactory instance using %s auth method", method)
+
+	switch method {
+	case authMethodBasic:
+		details.SetUser(c.Username)
+		details.SetPassword(c.Password)
+	case authMethodAPIKey:
+		// the user name is optional for API key authentication
+		if len(c.Username) > 0 {
+			details.SetUser(c.Username)
+		}
+
+		// check if the provided API key is actually an Access/Identity token,
+		// to ensure backwards compatibility with existing configurations
+		if !httpclient.IsApiKey(c.APIKey) {
+			logrus.Warn("provided api-key is not an API key, using it as an access token")
+
+			details.SetAccessToken(c.APIKey)
+
+			return nil
+		}
+
+		details.SetApiKey(c.APIKey)
+	case authMethodAccessToken:
+		details.SetAccessToken(c.Token)
+	case authMethodOIDC:
+		token, err := c.exchangeOIDCToken(client)
+		if err != nil {
+			return err
+		}
+
+		details.SetAccessToken(token)
+	case authMethodClientCert:
+		details.SetClientCertPath(c.CertPath)
+		details.SetClientCertKeyPath(c.CertKeyPath)
+	}
+
+	return nil
+}
+
+// oidcTokenRequest represents the payload for exchanging an OIDC ID token.
+type oidcTokenRequest struct {
+	GrantType        string `json:"grant_type"`
+	SubjectTokenType string `json:"subject_token_type"`
+	SubjectToken     string `json:"subject_token"`
+	ProviderName     string `json:"provider_name"`
+}
+
+// oidcTokenResponse represents the response from exchanging an OIDC ID token.
+type oidcTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	Username    string `json:"username"`
+}
+
+// exchangeOIDCToken exchanges the OIDC ID token for an access token
+// with the JFrog platform hosting the Artifactory instance.
+func (c *Config) exchangeOIDCToken(client *http.Client) (string, error) {
+	// the access service is hosted next to Artifactory on the JFrog platform
+	u := strings.TrimSuffix(c.URL, "artifactory/") + oidcTokenPath
+
+	logrus.Tracef("exchanging OIDC token with provider %s at %s", c.OIDCProvider, u)
+
+	payload, err := json.Marshal(&oidcTokenRequest{
+		GrantType:        "urn:ietf:params:oauth:grant-type:token-exchange",
+		SubjectTokenType: "urn:ietf:params:oauth:token-type:id_token",
+		SubjectToken:     c.OIDCToken,
+		ProviderName:     c.OIDCProvider,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to exchange OIDC token: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to exchange OIDC token: unexpected HTTP status %s", resp.Status)
+	}
+
+	token := new(oidcTokenResponse)
+
+	err = json.Unmarshal(body, token)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse OIDC token response: %w", err)
+	}
+
+	if len(token.AccessToken) == 0 {
+		return "", fmt.Errorf("unable to exchange OIDC token: no access token returned")
+	}
+
+	// capture the identity provided by the token exchange
+	c.oidcUsername = token.Username
+
+	// remove the exchanged access token from the client logs
+	c.redactor.Add(token.AccessToken)
+
+	return token.AccessToken, nil
+}
+
+// Identity returns the identity used for communication with the Artifactory instance.
+func (c *Config) Identity() string {
+	if name := c.knownIdentity(); len(name) > 0 {
+		return name
+	}
+
+	method, err := c.ResolveAuthMethod()
+	if err != nil {
+		return "anonymous"
+	}
+
+	return fmt.Sprintf("unknown %s identity", method)
+}
+
+// knownIdentity returns the name of the identity used for communication
+// with the Artifactory instance, or an empty string when it is unknown.
+func (c *Config) knownIdentity() string {
+	method, err := c.ResolveAuthMethod()
+	if err != nil {
+		return ""
+	}
+
+	switch method {
+	case authMethodBasic, authMethodAPIKey:
+		return c.Username
+	case authMethodAccessToken:
+		return jfrogauth.ExtractUsernameFromAccessToken(c.Token)
+	case authMethodOIDC:
+		return c.oidcUsername
+	case authMethodClientCert:
+		certificate, err := tls.LoadX509KeyPair(c.CertPath, c.CertKeyPath)
+		if err == nil && len(certificate.Certificate) > 0 {
+			leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+			if err == nil {
+				return leaf.Subject.CommonName
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,460 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_Config_ResolveAuthMethod(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		config  *Config
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "token",
+			config: &Config{Token: mock.Token},
+			want:   authMethodAccessToken,
+		},
+		{
+			name:   "token over everything",
+			config: &Config{Token: mock.Token, OIDCToken: mock.OIDCToken, APIKey: mock.APIKey, Username: mock.Username, Password: mock.Password},
+			want:   authMethodAccessToken,
+		},
+		{
+			name:   "api key over oidc",
+			config: &Config{OIDCToken: mock.OIDCToken, APIKey: mock.APIKey},
+			want:   authMethodAPIKey,
+		},
+		{
+			// Vela provides the ID token to every step through VELA_ID_TOKEN
+			name:   "basic over oidc",
+			config: &Config{OIDCToken: mock.OIDCToken, OIDCProvider: mock.OIDCProvider, Username: mock.Username, Password: mock.Password},
+			want:   authMethodBasic,
+		},
+		{
+			name:   "oidc",
+			config: &Config{OIDCToken: mock.OIDCToken, OIDCProvider: mock.OIDCProvider},
+			want:   authMethodOIDC,
+		},
+		{
+			name:   "oidc over client cert",
+			config: &Config{OIDCToken: mock.OIDCToken, Client: &Client{CertPath: "cert.pem", CertKeyPath: "key.pem"}},
+			want:   authMethodOIDC,
+		},
+		{
+			name:   "api key over basic",
+			config: &Config{APIKey: mock.APIKey, Username: mock.Username, Password: mock.Password},
+			want:   authMethodAPIKey,
+		},
+		{
+			name:   "api key with username",
+			config: &Config{APIKey: mock.APIKey, Username: mock.Username},
+			want:   authMethodAPIKey,
+		},
+		{
+			name:   "basic",
+			config: &Config{Username: mock.Username, Password: mock.Password},
+			want:   authMethodBasic,
+		},
+		{
+			name:   "basic over client cert",
+			config: &Config{Username: mock.Username, Password: mock.Password, Client: &Client{CertPath: "cert.pem", CertKeyPath: "key.pem"}},
+			want:   authMethodBasic,
+		},
+		{
+			name:   "client cert",
+			config: &Config{Client: &Client{CertPath: "cert.pem", CertKeyPath: "key.pem"}},
+			want:   authMethodClientCert,
+		},
+		{
+			name:   "explicit basic over token",
+			config: &Config{AuthMethod: "basic", Token: mock.Token, Username: mock.Username, Password: mock.Password},
+			want:   authMethodBasic,
+		},
+		{
+			name:   "explicit method is case insensitive",
+			config: &Config{AuthMethod: "API_KEY", Token: mock.Token, APIKey: mock.APIKey},
+			want:   authMethodAPIKey,
+		},
+		{
+			name:    "explicit invalid method",
+			config:  &Config{AuthMethod: "kerberos", Token: mock.Token},
+			wantErr: ErrInvalidAuthMethod,
+		},
+		{
+			name:    "username only",
+			config:  &Config{Username: mock.Username},
+			wantErr: ErrNoCredentials,
+		},
+		{
+			name:    "password only",
+			config:  &Config{Password: mock.Password},
+			wantErr: ErrNoCredentials,
+		},
+		{
+			name:    "cert without key",
+			config:  &Config{Client: &Client{CertPath: "cert.pem"}},
+			wantErr: ErrNoCredentials,
+		},
+		{
+			name:    "no credentials",
+			config:  &Config{},
+			wantErr: ErrNoCredentials,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.config.ResolveAuthMethod()
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("ResolveAuthMethod returned err %v, want %v", err, test.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("ResolveAuthMethod returned err: %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("ResolveAuthMethod is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Config_Validate_AuthMethod(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{
+			name:   "token only",
+			config: &Config{Token: mock.Token},
+		},
+		{
+			name:   "basic",
+			config: &Config{AuthMethod: "basic", Username: mock.Username, Password: mock.Password},
+		},
+		{
+			name:    "basic without password",
+			config:  &Config{AuthMethod: "basic", Username: mock.Username, APIKey: mock.APIKey},
+			wantErr: true,
+		},
+		{
+			name:    "basic without username",
+			config:  &Config{AuthMethod: "basic", Password: mock.Password},
+			wantErr: true,
+		},
+		{
+			name:   "api key",
+			config: &Config{AuthMethod: "api_key", APIKey: mock.APIKey},
+		},
+		{
+			name:    "api key without api key",
+			config:  &Config{AuthMethod: "api_key", Username: mock.Username, Password: mock.Password},
+			wantErr: true,
+		},
+		{
+			name:   "access token",
+			config: &Config{AuthMethod: "access_token", Token: mock.Token},
+		},
+		{
+			name:    "access token without token",
+			config:  &Config{AuthMethod: "access_token", APIKey: mock.APIKey},
+			wantErr: true,
+		},
+		{
+			name:   "oidc",
+			config: &Config{AuthMethod: "oidc", OIDCToken: mock.OIDCToken, OIDCProvider: mock.OIDCProvider},
+		},
+		{
+			name:    "oidc without provider",
+			config:  &Config{AuthMethod: "oidc", OIDCToken: mock.OIDCToken},
+			wantErr: true,
+		},
+		{
+			name:    "oidc without token",
+			config:  &Config{AuthMethod: "oidc", OIDCProvider: mock.OIDCProvider},
+			wantErr: true,
+		},
+		{
+			name:   "client cert",
+			config: &Config{AuthMethod: "client_cert", Client: &Client{CertPath: "cert.pem", CertKeyPath: "key.pem"}},
+		},
+		{
+			name:    "client cert without client",
+			config:  &Config{AuthMethod: "client_cert"},
+			wantErr: true,
+		},
+		{
+			name:    "client cert without key",
+			config:  &Config{AuthMethod: "client_cert", Client: &Client{CertPath: "cert.pem"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid method",
+			config:  &Config{AuthMethod: "kerberos", Token: mock.Token},
+			wantErr: true,
+		},
+		{
+			name:   "dry run without credentials",
+			config: &Config{DryRun: true},
+		},
+		{
+			name:   "dry run with incomplete credentials",
+			config: &Config{AuthMethod: "basic", DryRun: true, Username: mock.Username},
+		},
+		{
+			name:    "dry run with invalid method",
+			config:  &Config{AuthMethod: "kerberos", DryRun: true},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.Action = "copy"
+			test.config.URL = mock.InvalidArtifactoryServerURL
+
+			err := test.config.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Config_New_OIDC(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	c := &Config{
+		Action:       "copy",
+		AuthMethod:   "oidc",
+		OIDCToken:    mock.OIDCToken,
+		OIDCProvider: mock.OIDCProvider,
+		URL:          s.URL,
+		Client: &Client{
+			Retries:            3,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	got, err := c.New()
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	if got == nil {
+		t.Errorf("New is nil")
+	}
+
+	if (*got).GetConfig().GetServiceDetails().GetAccessToken() != mock.Token {
+		t.Errorf("New did not set access token from OIDC token exchange")
+	}
+
+	if c.Identity() != mock.Username {
+		t.Errorf("Identity is %v, want %v", c.Identity(), mock.Username)
+	}
+}
+
+func TestArtifactory_Config_New_OIDC_Error(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	c := &Config{
+		Action:       "copy",
+		AuthMethod:   "oidc",
+		OIDCToken:    "invalid",
+		OIDCProvider: mock.OIDCProvider,
+		URL:          s.URL,
+		Client: &Client{
+			Retries:            0,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	_, err := c.New()
+	if err == nil {
+		t.Errorf("New should have returned err")
+	}
+}
+
+func TestArtifactory_Config_New_AccessToken(t *testing.T) {
+	// setup types
+	c := &Config{
+		Action:   "copy",
+		Token:    mock.Token,
+		APIKey:   mock.APIKey,
+		URL:      mock.InvalidArtifactoryServerURL,
+		Username: mock.Username,
+		Password: mock.Password,
+		Client: &Client{
+			Retries:            3,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	got, err := c.New()
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	details := (*got).GetConfig().GetServiceDetails()
+
+	if details.GetAccessToken() != mock.Token {
+		t.Errorf("New access token is %v, want %v", details.GetAccessToken(), mock.Token)
+	}
+
+	if len(details.GetApiKey()) > 0 || len(details.GetPassword()) > 0 {
+		t.Errorf("New should only set credentials for the access_token auth method")
+	}
+}
+
+func TestArtifactory_Config_New_Basic(t *testing.T) {
+	// setup types
+	c := &Config{
+		Action:     "copy",
+		AuthMethod: "basic",
+		Token:      mock.Token,
+		URL:        mock.InvalidArtifactoryServerURL,
+		Username:   mock.Username,
+		Password:   mock.Password,
+		Client: &Client{
+			Retries:            3,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	got, err := c.New()
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	details := (*got).GetConfig().GetServiceDetails()
+
+	if details.GetUser() != mock.Username || details.GetPassword() != mock.Password {
+		t.Errorf("New did not set user and password for basic auth method")
+	}
+
+	if len(details.GetAccessToken()) > 0 {
+		t.Errorf("New should not set access token for basic auth method")
+	}
+}
+
+func TestArtifactory_Plugin_Exec_Preflight_Error(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Config: &Config{
+			Action:    "docker-promote",
+			Token:     mock.Token,
+			Preflight: true,
+			URL:       mock.InvalidArtifactoryServerURL,
+			Client: &Client{
+				Retries:            0,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			TargetRepo:     "docker",
+			DockerRegistry: "github/octocat",
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestArtifactory_Config_Identity_ClientCert(t *testing.T) {
+	// setup types
+	certPath, keyPath := writeClientCert(t, "octocat")
+
+	c := &Config{
+		Client: &Client{
+			CertPath:    certPath,
+			CertKeyPath: keyPath,
+		},
+	}
+
+	if c.Identity() != "octocat" {
+		t.Errorf("Identity is %v, want %v", c.Identity(), "octocat")
+	}
+}
+
+// writeClientCert creates a self-signed client certificate and key for testing.
+func writeClientCert(t *testing.T, name string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	err = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write certificate: %v", err)
+	}
+
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write key: %v", err)
+	}
+
+	return certPath, keyPath
+}
@@ -4,8 +4,8 @@ package main
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -15,7 +15,6 @@ import (
 	"github.com/jfrog/jfrog-client-go/artifactory/auth"
 	"github.com/jfrog/jfrog-client-go/auth/cert"
 	"github.com/jfrog/jfrog-client-go/config"
-	"github.com/jfrog/jfrog-client-go/http/httpclient"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/sirupsen/logrus"
 )
@@ -24,10 +23,18 @@ import (
 type Config struct {
 	// Action to perform against the Artifactory instance
 	Action string
+	// AuthMethod is the method used for authenticating with the Artifactory instance
+	AuthMethod string
 	// Token for communication with the Artifactory instance
 	Token string
 	// API key for communication with the Artifactory instance
 	APIKey string
+	// OIDCToken is the OIDC ID token exchanged for an access token with the Artifactory instance
+	OIDCToken string
+	// OIDCProvider is the name of the OIDC provider configured in the Artifactory instance
+	OIDCProvider string
+	// Preflight enables verifying connectivity, credentials and permissions before performing the action
+	Preflight bool
 	// DryRun enables pretending to perform the action against the Artifactory instance
 	DryRun bool
 	// Username for communication with the Artifactory instance
@@ -38,6 +45,11 @@ type Config struct {
 	URL string
 	// Client represents the HTTP client configurations for interacting with Artifactory
 	*Client
+
+	// oidcUsername is the identity returned from exchanging the OIDC token
+	oidcUsername string
+	// redactor removes secrets from the client logs
+	redactor *redactor
 }
 
 // Client represents the HTTP client configurations for interacting with Artifactory.
@@ -52,6 +64,11 @@ type Client struct {
 	CertKeyPath string
 	// InsecureTLS enables insecure TLS communication with Artifactory
 	InsecureTLS bool
+	// TraceFile is the path to record every request and response sent to Artifactory
+	TraceFile string
+
+	// recorder records requests and responses when a trace file is provided
+	recorder *traceRecorder
 }
 
 // New creates an Artifactory client for managing artifacts.
@@ -69,34 +86,14 @@ func (c *Config) New() (*artifactory.ArtifactoryServicesManager, error) {
 
 	// set URL for Artifactory details
 	details.SetUrl(c.URL)
-	// check if username is provided
-	if len(c.Username) > 0 {
-		// set user name for Artifactory details
-		details.SetUser(c.Username)
-	}
 
-	// check if Access/Identity token is provided
-	if len(c.Token) > 0 && !httpclient.IsApiKey(c.Token) {
-		// set Access/Identity token for Artifactory details
-		details.SetAccessToken(c.APIKey)
-	} else if len(c.APIKey) > 0 && httpclient.IsApiKey(c.APIKey) { // check if API key is provided
-		// set API key for Artifactory details
-		details.SetApiKey(c.APIKey)
-	} else if len(c.APIKey) > 0 && !httpclient.IsApiKey(c.APIKey) {
-		// set Access/Identity token for Artifactory details
-		details.SetAccessToken(c.APIKey)
-	}
-
-	// check if password is provided
-	if len(c.Password) > 0 {
-		// set password for Artifactory details
-		details.SetPassword(c.Password)
-	}
+	// create redactor to remove secrets from the client logs
+	c.redactor = newRedactor(c.Token, c.APIKey, c.Password, c.OIDCToken)
 
 	// set logger for Artifactory client
 	log.SetLogger(
-		// create new logger for Artifactory client
-		log.NewLogger(log.INFO, os.Stdout),
+		// create new logger for Artifactory client that routes through logrus
+		newJFrogLogger(c.redactor),
 	)
 
 	// set default HTTP retries and retry wait in milliseconds
@@ -118,6 +115,9 @@ func (c *Config) New() (*artifactory.ArtifactoryServicesManager, error) {
 	retryClient := retryablehttp.NewClient()
 	retryClient.RetryMax = c.Retries
 	retryClient.RetryWaitMin = time.Millisecond * time.Duration(c.RetryWaitMilliSecs)
+	retryLogger := newRetryLogger(c.redactor)
+	retryClient.Logger = retryLogger
+	retryClient.RequestLogHook = retryLogger.requestLogHook
 
 	transport := cleanhttp.DefaultPooledTransport()
 
@@ -136,10 +136,31 @@ func (c *Config) New() (*artifactory.ArtifactoryServicesManager, error) {
 
 	retryClient.HTTPClient.Transport = transport
 
+	// check if a trace file is provided
+	if len(c.TraceFile) > 0 {
+		logrus.Debugf("recording HTTP trace to %s", c.TraceFile)
+
+		// record every request and response sent to Artifactory
+		c.recorder = newTraceRecorder(c.redactor)
+		retryClient.HTTPClient.Transport = c.recorder.Transport(transport)
+	}
+
 	// apply a custom retry policy that has been modified to retry on 403 responses
 	// using an incrementing backoff
 	retryClient.CheckRetry = RetryPolicy
 
+	// check if credentials are provided for an auth method
+	method, err := c.ResolveAuthMethod()
+	if err == nil {
+		// set credentials for Artifactory details
+		err = c.setAuth(details, retryClient.StandardClient(), method)
+		if err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, ErrNoCredentials) {
+		return nil, err
+	}
+
 	// create new Artifactory config from details
 	config, err := config.NewConfigBuilder().
 		SetServiceDetails(details).
@@ -163,6 +184,23 @@ func (c *Config) New() (*artifactory.ArtifactoryServicesManager, error) {
 	return &client, nil
 }
 
+// WriteTrace saves the recorded HTTP trace to the trace file.
+func (c *Config) WriteTrace() {
+	// check if HTTP requests were recorded
+	if c.Client == nil || c.recorder == nil {
+		return
+	}
+
+	err := c.recorder.Write(c.TraceFile)
+	if err != nil {
+		logrus.Warnf("unable to write HTTP trace to %s: %v", c.TraceFile, err)
+
+		return
+	}
+
+	logrus.Infof("HTTP trace written to %s", c.TraceFile)
+}
+
 // Validate verifies the Config is properly configured.
 func (c *Config) Validate() error {
 	logrus.Trace("validating config plugin configuration")
@@ -177,17 +215,21 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("no config url provided")
 	}
 
-	// check if dry run is disabled
-	if !c.DryRun {
-		// verify username is provided if APIKey is empty
-		if len(c.Username) == 0 && len(c.APIKey) == 0 {
-			return fmt.Errorf("no config username provided")
+	// resolve the auth method from the provided credentials
+	method, err := c.ResolveAuthMethod()
+	if err != nil {
+		// allow pretending to perform the action without credentials
+		if c.DryRun && errors.Is(err, ErrNoCredentials) {
+			return nil
 		}
 
-		// verify API key or password are provided
-		if len(c.APIKey) == 0 && len(c.Password) == 0 {
-			return fmt.Errorf("no config api-key or password provided")
-		}
+		return err
+	}
+
+	// verify credentials are provided for the auth method
+	err = c.validateAuth(method)
+	if err != nil && !c.DryRun {
+		return err
 	}
 
 	return nil
@@ -4,10 +4,10 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
-	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/sirupsen/logrus"
 )
 
@@ -15,6 +15,8 @@ const copyAction = "copy"
 
 // Copy represents the plugin configuration for copy information.
 type Copy struct {
+	Selection
+
 	// Flat is a flag that enables removing source file directory hierarchy
 	Flat bool
 	// Recursive is a flag that enables copying sub-directories from source
@@ -23,29 +25,50 @@ type Copy struct {
 	Path string
 	// Target is the path to copy artifact(s) to
 	Target string
+	// Assert are the properties the artifact(s) must or must not have to be copied
+	Assert PropAssertion
 }
 
 // Exec formats and runs the commands for copying artifacts in Artifactory.
 func (c *Copy) Exec(cli artifactory.ArtifactoryServicesManager) error {
 	logrus.Trace("running copy with provided configuration")
 
+	start := time.Now()
+	logger := actionLogger(copyAction, c.Path).WithField("target", c.Target)
+
+	// log the artifact(s) selected for copying
+	err := c.searchSelection(cli, logger, c.CommonParams(c.Path, c.Recursive))
+	if err != nil {
+		return err
+	}
+
+	// verify the properties of the artifact(s) before copying any of them
+	if c.Assert.Enabled() {
+		err = c.Assert.Assert(cli, logger, c.CommonParams(c.Path, c.Recursive))
+		if err != nil {
+			return err
+		}
+	}
+
 	// create new copy parameters
 	p := services.NewMoveCopyParams()
 
 	// add copy configuration to copy parameters
-	p.CommonParams = &utils.CommonParams{
-		Pattern:   c.Path,
-		Recursive: c.Recursive,
-		Target:    c.Target,
-	}
+	p.CommonParams = c.CommonParams(c.Path, c.Recursive)
+	p.Target = c.Target
 	p.Flat = c.Flat
 
 	// send API call to copy artifacts in Artifactory
-	_, _, err := cli.Copy(p)
+	success, failed, err := cli.Copy(p)
 	if err != nil {
 		return err
 	}
 
+	withDuration(logger, start).WithFields(logrus.Fields{
+		"success": success,
+		"failed":  failed,
+	}).Infof("Copied %d artifact(s) to %s", success, c.Target)
+
 	return nil
 }
 
@@ -53,9 +76,16 @@ func (c *Copy) Exec(cli artifactory.ArtifactoryServicesManager) error {
 func (c *Copy) Validate() error {
 	logrus.Trace("validating copy plugin configuration")
 
-	// verify path is provided
-	if len(c.Path) == 0 {
-		return fmt.Errorf("no copy path provided")
+	// verify the selection is valid
+	err := c.Selection.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid copy selection provided: %w", err)
+	}
+
+	// verify path or aql is provided
+	err = c.validatePath(copyAction, c.Path)
+	if err != nil {
+		return err
 	}
 
 	// verify target is provided
@@ -63,5 +93,11 @@ func (c *Copy) Validate() error {
 		return fmt.Errorf("no copy target provided")
 	}
 
+	// verify the property assertions are valid
+	err = c.Assert.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid copy assertion provided: %w", err)
+	}
+
 	return nil
 }
@@ -4,10 +4,10 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
-	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/sirupsen/logrus"
 )
 
@@ -15,6 +15,8 @@ const deleteAction = "delete"
 
 // Delete represents the plugin configuration for delete information.
 type Delete struct {
+	Selection
+
 	// Recursive is a flag that enables removing sub-directories for the artifact(s) in the path
 	Recursive bool
 	// Path is the target path to artifact(s) to remove
@@ -25,14 +27,14 @@ type Delete struct {
 func (d *Delete) Exec(cli artifactory.ArtifactoryServicesManager) error {
 	logrus.Trace("running delete with provided configuration")
 
+	start := time.Now()
+	logger := actionLogger(deleteAction, d.Path)
+
 	// create new delete parameters
 	p := services.NewDeleteParams()
 
 	// add delete configuration to delete parameters
-	p.CommonParams = &utils.CommonParams{
-		Pattern:   d.Path,
-		Recursive: d.Recursive,
-	}
+	p.CommonParams = d.CommonParams(d.Path, d.Recursive)
 
 	// send API call to capture paths to artifacts in Artifactory
 	paths, err := cli.GetPathsToDelete(p)
@@ -40,12 +42,22 @@ func (d *Delete) Exec(cli artifactory.ArtifactoryServicesManager) error {
 		return err
 	}
 
+	defer paths.Close()
+
+	// log the artifact(s) selected for removal
+	err = d.logSelection(logger, paths)
+	if err != nil {
+		return err
+	}
+
 	// send API call to delete artifacts in Artifactory
-	_, err = cli.DeleteFiles(paths)
+	deleted, err := cli.DeleteFiles(paths)
 	if err != nil {
 		return err
 	}
 
+	withDuration(logger, start).WithField("success", deleted).Infof("Deleted %d artifact(s)", deleted)
+
 	return nil
 }
 
@@ -53,9 +65,16 @@ func (d *Delete) Exec(cli artifactory.ArtifactoryServicesManager) error {
 func (d *Delete) Validate() error {
 	logrus.Trace("validating delete plugin configuration")
 
-	// verify path is provided
-	if len(d.Path) == 0 {
-		return fmt.Errorf("no delete path provided")
+	// verify the selection is valid
+	err := d.Selection.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid delete selection provided: %w", err)
+	}
+
+	// verify path or aql is provided
+	err = d.validatePath(deleteAction, d.Path)
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -0,0 +1,366 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/sirupsen/logrus"
+)
+
+const dockerCleanupAction = "docker-cleanup"
+
+// defaultKeep is the default number of the newest tags kept for an image.
+const defaultKeep = 10
+
+// DockerCleanup represents the plugin configuration for removing old tags of an image.
+type DockerCleanup struct {
+	// Repo is the Docker repository in Artifactory containing the image
+	Repo string
+	// Image is the path to the image in the Docker registry
+	Image string
+	// Keep is the number of the newest tags to keep for the image
+	Keep int
+	// Protect are glob patterns for tags that are never removed
+	Protect []string
+	// DryRun enables logging the tags that would be removed without removing them
+	DryRun bool
+}
+
+// aqlItem represents an item returned by an Artifactory Query Language search.
+type aqlItem struct {
+	Repo       string        `json:"repo"`
+	Path       string        `json:"path"`
+	Name       string        `json:"name"`
+	Created    time.Time     `json:"created"`
+	SHA256     string        `json:"sha256"`
+	Properties []aqlProperty `json:"properties"`
+}
+
+// aqlProperty represents a property of an item returned by an Artifactory Query Language search.
+type aqlProperty struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// aqlResults represents the results of an Artifactory Query Language search.
+type aqlResults struct {
+	Results []*aqlItem `json:"results"`
+}
+
+// cleanupTag represents the plan for a tag of the image.
+type cleanupTag struct {
+	// Name is the name of the tag
+	Name string
+	// Manifest is the manifest stored for the tag
+	Manifest *aqlItem
+	// Reason explains why the tag is kept (the tag is removed when empty)
+	Reason string
+}
+
+// Exec formats and runs the commands for removing old tags of an image in Artifactory.
+func (d *DockerCleanup) Exec(cli artifactory.ArtifactoryServicesManager) error {
+	logrus.Trace("running docker-cleanup with provided configuration")
+
+	start := time.Now()
+	logger := actionLogger(dockerCleanupAction, fmt.Sprintf("%s/%s", d.Repo, d.Image))
+
+	// decide which tags to keep and remove before removing any tag
+	tags, err := d.Plan(cli)
+	if err != nil {
+		return err
+	}
+
+	var remove []*cleanupTag
+
+	logger.Infof("Cleanup plan for %d tag(s) of %s:", len(tags), d.Image)
+
+	for _, tag := range tags {
+		entry := logger.WithField("artifact", fmt.Sprintf("%s:%s", d.Image, tag.Name))
+
+		if len(tag.Reason) > 0 {
+			entry.Infof("  [keep] %s: %s", tag.Name, tag.Reason)
+
+			continue
+		}
+
+		entry.Infof("  [delete] %s", tag.Name)
+
+		remove = append(remove, tag)
+	}
+
+	if d.DryRun {
+		withDuration(logger, start).Infof("Dry run enabled, skipping removal of %d tag(s)", len(remove))
+
+		return nil
+	}
+
+	var failed int
+
+	for _, tag := range remove {
+		entry := logger.WithField("artifact", fmt.Sprintf("%s:%s", d.Image, tag.Name))
+		target := fmt.Sprintf("%s/%s/%s", d.Repo, d.Image, tag.Name)
+
+		// send API call to remove the tag from the repository
+		resp, _, err := apiDelete(cli, target)
+		if err == nil && resp.StatusCode >= http.StatusBadRequest {
+			err = fmt.Errorf("%s", resp.Status)
+		}
+
+		if err != nil {
+			entry.Errorf("  [failed] unable to remove %s: %v", target, err)
+
+			failed++
+
+			continue
+		}
+
+		entry.Infof("  [deleted] removed %s", target)
+	}
+
+	withDuration(logger, start).WithField("success", len(remove)-failed).
+		Infof("Removed %d of %d tag(s) and kept %d tag(s)", len(remove)-failed, len(remove), len(tags)-len(remove))
+
+	if failed > 0 {
+		return fmt.Errorf("unable to remove %d of %d tags for %s", failed, len(remove), d.Image)
+	}
+
+	return nil
+}
+
+// Plan returns every tag of the image, ordered from newest to oldest, with
+// the reason each tag is kept. Tags without a reason are safe to remove.
+func (d *DockerCleanup) Plan(cli artifactory.ArtifactoryServicesManager) ([]*cleanupTag, error) {
+	names, err := listImageTags(cli, d.Repo, d.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := d.manifests(cli)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]*cleanupTag, 0, len(names))
+
+	for _, name := range names {
+		tags = append(tags, &cleanupTag{Name: name, Manifest: manifests[name]})
+	}
+
+	// order the tags from newest to oldest, keeping tags without a manifest first
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Manifest == nil || tags[j].Manifest == nil {
+			return tags[i].Manifest == nil && tags[j].Manifest != nil
+		}
+
+		return tags[i].Manifest.Created.After(tags[j].Manifest.Created)
+	})
+
+	var (
+		newest     int
+		candidates []*cleanupTag
+	)
+
+	for _, tag := range tags {
+		if tag.Manifest == nil {
+			tag.Reason = "no manifest found for the tag"
+
+			continue
+		}
+
+		newest++
+
+		if newest <= d.Keep {
+			tag.Reason = fmt.Sprintf("one of the %d newest tags", d.Keep)
+
+			continue
+		}
+
+		if pattern, ok := d.protected(tag.Name); ok {
+			tag.Reason = fmt.Sprintf("protected by pattern %s", pattern)
+
+			continue
+		}
+
+		if promoted, ok := tag.Manifest.property(promotedOnProperty); ok {
+			tag.Reason = fmt.Sprintf("promoted on %s", promoted)
+
+			continue
+		}
+
+		candidates = append(candidates, tag)
+	}
+
+	// never remove a tag whose manifest is referenced by another repository
+	references, err := d.references(cli, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tag := range candidates {
+		if reference, ok := references[tag.Manifest.SHA256]; ok {
+			tag.Reason = fmt.Sprintf("referenced by %s/%s", reference.Repo, reference.Path)
+		}
+	}
+
+	return tags, nil
+}
+
+// protected returns the pattern protecting the tag, if any.
+func (d *DockerCleanup) protected(tag string) (string, bool) {
+	for _, pattern := range d.Protect {
+		ok, _ := path.Match(pattern, tag)
+		if ok {
+			return pattern, true
+		}
+	}
+
+	return "", false
+}
+
+// manifests returns the manifest for each tag of the image.
+func (d *DockerCleanup) manifests(cli artifactory.ArtifactoryServicesManager) (map[string]*aqlItem, error) {
+	query := map[string]any{
+		"repo": d.Repo,
+		"path": map[string]string{"$match": d.Image + "/*"},
+		"name": map[string]string{"$match": "*manifest.json"},
+	}
+
+	items, err := aqlSearch(cli, query, "repo", "path", "name", "created", "sha256", "property.*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to search manifests for %s in %s: %w", d.Image, d.Repo, err)
+	}
+
+	manifests := make(map[string]*aqlItem)
+
+	for _, item := range items {
+		// ignore the manifests of images nested below the image
+		if path.Dir(item.Path) != d.Image {
+			continue
+		}
+
+		manifests[path.Base(item.Path)] = item
+	}
+
+	return manifests, nil
+}
+
+// references returns the manifests stored in other repositories for the tags, by checksum.
+func (d *DockerCleanup) references(cli artifactory.ArtifactoryServicesManager, tags []*cleanupTag) (map[string]*aqlItem, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	checksums := make([]map[string]string, 0, len(tags))
+
+	for _, tag := range tags {
+		checksums = append(checksums, map[string]string{"sha256": tag.Manifest.SHA256})
+	}
+
+	query := map[string]any{
+		"repo": map[string]string{"$ne": d.Repo},
+		"name": map[string]string{"$match": "*manifest.json"},
+		"$or":  checksums,
+	}
+
+	items, err := aqlSearch(cli, query, "repo", "path", "name", "sha256")
+	if err != nil {
+		return nil, fmt.Errorf("unable to search references for %s in %s: %w", d.Image, d.Repo, err)
+	}
+
+	references := make(map[string]*aqlItem)
+
+	for _, item := range items {
+		references[item.SHA256] = item
+	}
+
+	return references, nil
+}
+
+// property returns the value of the property on the item.
+func (i *aqlItem) property(key string) (string, bool) {
+	for _, property := range i.Properties {
+		if property.Key == key {
+			return property.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// aqlSearch sends an Artifactory Query Language search for the items
+// matching the query and returns the fields included for each item.
+func aqlSearch(cli artifactory.ArtifactoryServicesManager, query map[string]any, fields ...string) ([]*aqlItem, error) {
+	criteria, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	include, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	aql := fmt.Sprintf("items.find(%s).include(%s)", criteria, include[1:len(include)-1])
+
+	logrus.Tracef("sending AQL search %s", aql)
+
+	// send API call to search for the items
+	r, err := cli.Aql(aql)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	results := new(aqlResults)
+
+	err = json.Unmarshal(body, results)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse AQL results: %w", err)
+	}
+
+	return results.Results, nil
+}
+
+// Validate verifies the DockerCleanup is properly configured.
+func (d *DockerCleanup) Validate() error {
+	logrus.Trace("validating docker-cleanup plugin configuration")
+
+	// verify a repo is provided
+	if len(d.Repo) == 0 {
+		return fmt.Errorf("no docker-cleanup repository provided")
+	}
+
+	// verify an image is provided
+	if len(d.Image) == 0 {
+		return fmt.Errorf("no docker-cleanup image provided")
+	}
+
+	// verify the number of tags to keep is valid
+	if d.Keep < 0 {
+		return fmt.Errorf("invalid docker-cleanup keep %d provided", d.Keep)
+	}
+
+	// verify the protect patterns are well formed
+	for _, pattern := range d.Protect {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return fmt.Errorf("invalid docker-cleanup protect pattern %s provided: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
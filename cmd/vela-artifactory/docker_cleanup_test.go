@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_DockerCleanup_Plan(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	c := &Config{
+		URL:      s.URL,
+		Username: mock.Username,
+		Password: mock.Password,
+		Client: &Client{
+			Retries:            3,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	cli, err := c.New()
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	d := &DockerCleanup{
+		Repo:    "docker",
+		Image:   "docker-dev",
+		Keep:    1,
+		Protect: []string{"0.1.*"},
+	}
+
+	want := map[string]string{
+		"0.5.0": "one of the 1 newest tags",
+		"0.4.0": "promoted on 2024-04-02T12:00:00Z",
+		"0.3.0": "referenced by docker-prod/docker-dev/0.3.0",
+		"0.2.0": "",
+		"0.1.0": "protected by pattern 0.1.*",
+	}
+
+	got, err := d.Plan(*cli)
+	if err != nil {
+		t.Fatalf("Plan returned err: %v", err)
+	}
+
+	var order []string
+
+	for _, tag := range got {
+		order = append(order, tag.Name)
+
+		if tag.Reason != want[tag.Name] {
+			t.Errorf("Plan reason for %s is %q, want %q", tag.Name, tag.Reason, want[tag.Name])
+		}
+	}
+
+	if !reflect.DeepEqual(order, []string{"0.5.0", "0.4.0", "0.3.0", "0.2.0", "0.1.0"}) {
+		t.Errorf("Plan order is %v, want newest to oldest", order)
+	}
+}
+
+func TestArtifactory_DockerCleanup_Exec(t *testing.T) {
+	// setup types
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			deleted = append(deleted, r.URL.Path)
+			mu.Unlock()
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	// setup tests
+	tests := []struct {
+		name   string
+		dryRun bool
+		want   []string
+	}{
+		{
+			name: "delete",
+			want: []string{"/docker/docker-dev/0.2.0", "/docker/docker-dev/0.1.0"},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deleted = nil
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "docker-cleanup",
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				DockerCleanup: &DockerCleanup{
+					Repo:   "docker",
+					Image:  "docker-dev",
+					Keep:   1,
+					DryRun: test.dryRun,
+				},
+			}
+
+			err := p.Exec()
+			if err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(deleted, test.want) {
+				t.Errorf("Exec deleted %v, want %v", deleted, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_DockerCleanup_Exec_Error(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-cleanup",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerCleanup: &DockerCleanup{
+			Repo:  "not-found",
+			Image: "docker-dev",
+			Keep:  1,
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestArtifactory_DockerCleanup_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		d       *DockerCleanup
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			d:    &DockerCleanup{Repo: "docker", Image: "docker-dev", Keep: 10, Protect: []string{"latest", "v*"}},
+		},
+		{
+			name:    "no repo",
+			d:       &DockerCleanup{Image: "docker-dev", Keep: 10},
+			wantErr: true,
+		},
+		{
+			name:    "no image",
+			d:       &DockerCleanup{Repo: "docker", Keep: 10},
+			wantErr: true,
+		},
+		{
+			name:    "negative keep",
+			d:       &DockerCleanup{Repo: "docker", Image: "docker-dev", Keep: -1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid protect pattern",
+			d:       &DockerCleanup{Repo: "docker", Image: "docker-dev", Keep: 10, Protect: []string{"["}},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.d.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultConcurrency is the default number of images promoted at the same time.
+const defaultConcurrency = 4
+
+// DockerImage represents the plugin configuration for an image to promote.
+type DockerImage struct {
+	// Image is the path to the source image in the Docker registry
+	Image string `json:"image"`
+	// TargetImage is the path to the target image in the Docker registry (uses 'Image' if empty)
+	TargetImage string `json:"target_image"`
+	// Tag is the name of the tag to promote (promotes all tags if empty)
+	Tag string `json:"tag"`
+	// TargetTags are the target tags to assign to the image after promotion (uses 'TargetTags' if empty)
+	TargetTags []string `json:"target_tags"`
+}
+
+// imageResult represents the outcome of promoting an image.
+type imageResult struct {
+	// Promote is the configuration used to promote the image
+	Promote *DockerPromote
+	// Tags are the outcome of promoting each source tag of the image
+	Tags []*tagResult
+	// Skipped is set when the image was not promoted because another image failed
+	Skipped bool
+	// Err is the error that stopped the promotion of the image
+	Err error
+	// Existing are the tags of the target image before it was promoted
+	Existing []string
+}
+
+// validateImages verifies the images are properly configured.
+func (p *DockerPromote) validateImages() error {
+	for _, image := range p.Images {
+		// verify an image is provided
+		if len(image.Image) == 0 {
+			return fmt.Errorf("no image provided for docker-promote images")
+		}
+
+		// verify each image is valid with the shared configuration
+		err := p.forImage(image).validateTags()
+		if err != nil {
+			return fmt.Errorf("invalid image %s provided: %w", image.Image, err)
+		}
+	}
+
+	if p.Concurrency < 0 {
+		return fmt.Errorf("invalid concurrency %d provided", p.Concurrency)
+	}
+
+	return nil
+}
+
+// forImage returns the configuration for promoting the image, using
+// the shared configuration for any values the image does not provide.
+func (p *DockerPromote) forImage(image *DockerImage) *DockerPromote {
+	promote := *p
+
+	promote.Images = nil
+	promote.DockerRegistry = image.Image
+	promote.TargetDockerRegistry = image.TargetImage
+	promote.SourceTag = image.Tag
+
+	if len(image.TargetTags) > 0 {
+		promote.TargetTags = image.TargetTags
+	}
+
+	return &promote
+}
+
+// execImages promotes every image concurrently.
+//
+// Every image is planned before any image is promoted, and no further
+// images are promoted once an image fails. With rollback enabled, the
+// tags already promoted to the target are removed when an image fails.
+func (p *DockerPromote) execImages(cli artifactory.ArtifactoryServicesManager) error {
+	start := time.Now()
+	logger := actionLogger(dockerPromoteAction, p.TargetRepo)
+	build := newBuildMetadata()
+
+	results := make([]*imageResult, len(p.Images))
+	plans := make([][]*promotion, len(p.Images))
+
+	// resolve every source and target tag before promoting any image
+	for i, image := range p.Images {
+		results[i] = &imageResult{Promote: p.forImage(image)}
+
+		promotions, err := results[i].Promote.plan(cli, build)
+		if err != nil {
+			return fmt.Errorf("unable to plan promotion for image %s: %w", image.Image, err)
+		}
+
+		plans[i] = promotions
+
+		// capture the tags of the target image, so rollback only removes the tags it creates
+		if p.Rollback && !p.DryRun {
+			results[i].Existing, err = results[i].Promote.targetTags(cli)
+			if err != nil {
+				return fmt.Errorf("unable to capture target tags for image %s: %w", image.Image, err)
+			}
+		}
+	}
+
+	concurrency := p.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	logger.Infof("Promoting %d images with a concurrency of %d", len(p.Images), concurrency)
+
+	var (
+		wg     sync.WaitGroup
+		failed atomic.Bool
+	)
+
+	jobs := make(chan int)
+
+	for range min(concurrency, len(p.Images)) {
+		wg.Go(func() {
+			for i := range jobs {
+				result := results[i]
+
+				// avoid promoting further images once an image has failed
+				if failed.Load() {
+					result.Skipped = true
+
+					continue
+				}
+
+				imageLogger := logger.WithField("artifact", result.Promote.DockerRegistry)
+
+				result.Tags, result.Err = result.Promote.promote(cli, imageLogger, plans[i], failed.Load)
+				if result.Err != nil {
+					failed.Store(true)
+				}
+			}
+		})
+	}
+
+	for i := range p.Images {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	p.reportImages(logger, results)
+
+	if !failed.Load() && p.DryRun {
+		withDuration(logger, start).Infof("Dry run enabled, planned the promotion of %d images", len(results))
+
+		return nil
+	}
+
+	if !failed.Load() {
+		withDuration(logger, start).Infof("Promoted %d images successfully", len(results))
+
+		return nil
+	}
+
+	err := imagesError(results)
+
+	if p.Rollback {
+		// rollback modifies the repositories through raw API calls the client dry run does not cover
+		if p.DryRun {
+			logger.Warn("Dry run enabled, skipping rollback of promoted images")
+
+			return err
+		}
+
+		p.rollback(cli, logger, results)
+	}
+
+	return err
+}
+
+// imagesError returns an error describing the images that failed to promote.
+func imagesError(results []*imageResult) error {
+	var (
+		images []string
+		first  error
+	)
+
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+
+		images = append(images, result.Promote.DockerRegistry)
+
+		if first == nil {
+			first = result.Err
+		}
+	}
+
+	return fmt.Errorf("unable to promote images %s: %w", strings.Join(images, ", "), first)
+}
+
+// targetTags returns the tags of the target image, or no tags when the target image does not exist.
+func (p *DockerPromote) targetTags(cli artifactory.ArtifactoryServicesManager) ([]string, error) {
+	tags, err := listImageTags(cli, p.TargetRepo, p.targetImage())
+	if errors.Is(err, errImageNotFound) {
+		return nil, nil
+	}
+
+	return tags, err
+}
+
+// targetImage returns the path to the target image in the Docker registry.
+func (p *DockerPromote) targetImage() string {
+	if len(p.TargetDockerRegistry) > 0 {
+		return p.TargetDockerRegistry
+	}
+
+	return p.DockerRegistry
+}
+
+// rollback reverts the tags promoted to the target for every image.
+//
+// Copied tags are removed from the target, unless the tag existed in the target
+// before the promotion. Moved tags are moved back to the source, or copied back
+// when the tag existed in the target, since the source image no longer exists.
+func (p *DockerPromote) rollback(cli artifactory.ArtifactoryServicesManager, logger *logrus.Entry, results []*imageResult) {
+	logger.Warn("Rolling back promoted images")
+
+	for _, result := range results {
+		promote := result.Promote
+
+		source := promote.SourceRepo
+		if len(source) == 0 {
+			source = promote.TargetRepo
+		}
+
+		target := promote.targetImage()
+
+		for _, tag := range result.Tags {
+			for _, targetTag := range tag.Promoted {
+				entry := logger.WithField("artifact", fmt.Sprintf("%s:%s", target, targetTag))
+
+				// never remove the image that was promoted
+				if source == promote.TargetRepo && target == promote.DockerRegistry && targetTag == tag.SourceTag {
+					entry.Warnf("  [skipped] %s:%s is the source image", target, targetTag)
+
+					continue
+				}
+
+				existed := slices.Contains(result.Existing, targetTag)
+
+				if !promote.Copy {
+					promote.restore(cli, entry, source, target, tag.SourceTag, targetTag, existed)
+
+					continue
+				}
+
+				// never remove a tag that existed before the promotion
+				if existed {
+					entry.Warnf("  [skipped] %s:%s existed in %s before the promotion", target, targetTag, promote.TargetRepo)
+
+					continue
+				}
+
+				path := fmt.Sprintf("%s/%s/%s", promote.TargetRepo, target, targetTag)
+
+				// send API call to remove the promoted tag from the target
+				resp, _, err := apiDelete(cli, path)
+				if err == nil && resp.StatusCode >= http.StatusBadRequest {
+					err = fmt.Errorf("%s", resp.Status)
+				}
+
+				if err != nil {
+					entry.Errorf("  [failed] unable to remove %s: %v", path, err)
+
+					continue
+				}
+
+				entry.Infof("  [rolled back] removed %s", path)
+			}
+		}
+	}
+}
+
+// restore promotes a moved tag from the target back to the source. The tag is
+// copied back when it existed in the target before the promotion.
+func (p *DockerPromote) restore(
+	cli artifactory.ArtifactoryServicesManager,
+	logger *logrus.Entry,
+	source, target, sourceTag, targetTag string,
+	existed bool,
+) {
+	// avoid assigning parameters via constructor to ensure promote endpoint is constructed properly
+	params := services.NewDockerPromoteParams("", "", "")
+
+	params.SourceRepo = p.TargetRepo
+	params.TargetRepo = source
+	params.SourceDockerImage = target
+	params.TargetDockerImage = p.DockerRegistry
+	params.SourceTag = targetTag
+	params.TargetTag = sourceTag
+	params.Copy = existed
+
+	// send API call to promote the tag back to the source
+	err := cli.PromoteDocker(params)
+	if err != nil {
+		logger.Errorf("  [failed] unable to restore %s:%s to %s: %v", target, targetTag, source, err)
+
+		return
+	}
+
+	logger.Infof("  [rolled back] restored %s/%s:%s from %s:%s", source, p.DockerRegistry, sourceTag, target, targetTag)
+}
+
+// reportImages logs the outcome of promoting each image.
+func (p *DockerPromote) reportImages(logger *logrus.Entry, results []*imageResult) {
+	logger.Infof("Promotion report for %d images:", len(results))
+
+	for _, result := range results {
+		entry := logger.WithField("artifact", result.Promote.DockerRegistry)
+
+		var promoted, planned []string
+
+		for _, tag := range result.Tags {
+			for _, targetTag := range tag.Promoted {
+				promoted = append(promoted, fmt.Sprintf("%s -> %s", tag.SourceTag, targetTag))
+			}
+
+			for _, targetTag := range tag.Planned {
+				planned = append(planned, fmt.Sprintf("%s -> %s", tag.SourceTag, targetTag))
+			}
+		}
+
+		switch {
+		case result.Skipped:
+			entry.Warnf("  [skipped] %s", result.Promote.DockerRegistry)
+		case result.Err != nil:
+			entry.Errorf("  [failed] %s: %v", result.Promote.DockerRegistry, result.Err)
+		case len(planned) > 0:
+			entry.Infof("  [dry run] %s: %s", result.Promote.DockerRegistry, strings.Join(planned, ", "))
+		default:
+			entry.Infof("  [promoted] %s: %s", result.Promote.DockerRegistry, strings.Join(promoted, ", "))
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_DockerPromote_Exec_Images(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			TargetRepo:  "docker",
+			TargetTags:  []string{"stable"},
+			Copy:        true,
+			Concurrency: 2,
+			RawImages: `
+- image: github/api
+  tag: 1.0.0
+- image: github/web
+  target_image: octocat/web
+  tag: 1.0.0
+  target_tags: [ "1", "1.0" ]
+- image: github/worker
+  tag: 1.0.0
+`,
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_Images_Rollback(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			TargetRepo:  "docker",
+			TargetTags:  []string{"stable"},
+			Copy:        true,
+			Rollback:    true,
+			Concurrency: 1,
+			Images: []*DockerImage{
+				{Image: "github/api", Tag: "1.0.0"},
+				{Image: "github/not-found", Tag: "1.0.0"},
+				{Image: "github/worker", Tag: "1.0.0"},
+			},
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Fatalf("Exec should have returned err")
+	}
+
+	var rolledBack, skipped bool
+
+	for _, entry := range hook.AllEntries() {
+		if strings.Contains(entry.Message, "[rolled back] removed docker/github/api/stable") {
+			rolledBack = true
+		}
+
+		if strings.Contains(entry.Message, "[skipped] github/worker") {
+			skipped = true
+		}
+	}
+
+	if !rolledBack {
+		t.Errorf("Exec did not roll back promoted image")
+	}
+
+	if !skipped {
+		t.Errorf("Exec did not skip remaining image")
+	}
+}
+
+func TestArtifactory_DockerPromote_Validate_Images(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		p    *DockerPromote
+	}{
+		{
+			name: "invalid raw images",
+			p: &DockerPromote{
+				TargetRepo: "docker",
+				RawImages:  "{ image: [",
+			},
+		},
+		{
+			name: "missing image",
+			p: &DockerPromote{
+				TargetRepo: "docker",
+				RawImages:  `[{"tag": "latest"}]`,
+			},
+		},
+		{
+			name: "invalid tag rules for image",
+			p: &DockerPromote{
+				TargetRepo: "docker",
+				TagRules:   []string{"major"},
+				RawImages:  `[{"image": "github/octocat", "tag": "latest"}]`,
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.p.Validate()
+			if err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+		})
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_Images_Rollback_Requests(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		copy bool
+		want []string
+	}{
+		{
+			// the stable tag of github/api existed in the target before the promotion
+			name: "copy",
+			copy: true,
+			want: []string{"DELETE /docker/github/web/stable"},
+		},
+		{
+			name: "move",
+			want: []string{
+				`POST {"targetRepo":"docker","dockerRepository":"github/api","targetDockerRepository":"github/api","tag":"stable","targetTag":"1.0.0","copy":true}`,
+				`POST {"targetRepo":"docker","dockerRepository":"github/web","targetDockerRepository":"github/web","tag":"stable","targetTag":"1.0.0","copy":false}`,
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				mu  sync.Mutex
+				got []string
+			)
+
+			handler := mock.Handlers()
+
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/api/docker/docker/v2/github/api/tags/list":
+					_, _ = w.Write([]byte(`{"name":"github/api","tags":["stable"]}`))
+
+					return
+				case r.Method == http.MethodDelete:
+					got = append(got, "DELETE "+r.URL.Path)
+					w.WriteHeader(http.StatusNoContent)
+
+					return
+				case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/v2/promote"):
+					body, _ := io.ReadAll(r.Body)
+
+					// record the promotions from the target tag back to the source
+					if strings.Contains(string(body), `"tag":"stable"`) {
+						got = append(got, "POST "+string(body))
+
+						return
+					}
+
+					r.Body = io.NopCloser(bytes.NewReader(body))
+				}
+
+				handler.ServeHTTP(w, r)
+			}))
+			defer s.Close()
+
+			p := &DockerPromote{
+				TargetRepo:  "docker",
+				TargetTags:  []string{"stable"},
+				Copy:        test.copy,
+				Rollback:    true,
+				Concurrency: 1,
+				Images: []*DockerImage{
+					{Image: "github/api", Tag: "1.0.0"},
+					{Image: "github/web", Tag: "1.0.0"},
+					{Image: "github/not-found", Tag: "1.0.0"},
+				},
+			}
+
+			cli, err := (&Config{
+				URL:      s.URL,
+				Username: mock.Username,
+				Password: mock.Password,
+				Client: &Client{
+					Retries:            3,
+					RetryWaitMilliSecs: 1,
+				},
+			}).New()
+			if err != nil {
+				t.Fatalf("unable to create client: %v", err)
+			}
+
+			err = p.execImages(*cli)
+			if err == nil {
+				t.Fatalf("execImages should have returned err")
+			}
+
+			sort.Strings(got)
+
+			if !slices.Equal(got, test.want) {
+				t.Errorf("execImages rollback sent %v, want %v", got, test.want)
+			}
+		})
+	}
+}
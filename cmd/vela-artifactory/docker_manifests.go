@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// mediaTypeDockerManifestList is the media type for a Docker manifest list.
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	// mediaTypeDockerManifest is the media type for a Docker image manifest.
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	// mediaTypeOCIImageIndex is the media type for an OCI image index.
+	mediaTypeOCIImageIndex = "application/vnd.oci.image.index.v1+json"
+	// mediaTypeOCIManifest is the media type for an OCI image manifest.
+	mediaTypeOCIManifest = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// manifestAccept is the Accept header for capturing an image manifest or manifest list.
+var manifestAccept = strings.Join([]string{
+	mediaTypeDockerManifestList,
+	mediaTypeOCIImageIndex,
+	mediaTypeDockerManifest,
+	mediaTypeOCIManifest,
+}, ", ")
+
+// platformManifest represents a platform specific manifest referenced by a manifest list.
+//
+// https://distribution.github.io/distribution/spec/manifest-v2-2/#manifest-list
+type platformManifest struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Platform  struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+		Variant      string `json:"variant,omitempty"`
+	} `json:"platform"`
+}
+
+// String returns the platform and digest for the manifest.
+func (m *platformManifest) String() string {
+	platform := fmt.Sprintf("%s/%s", m.Platform.OS, m.Platform.Architecture)
+
+	if len(m.Platform.Variant) > 0 {
+		platform = fmt.Sprintf("%s/%s", platform, m.Platform.Variant)
+	}
+
+	return fmt.Sprintf("%s (%s)", platform, m.Digest)
+}
+
+// manifestList represents a Docker manifest list or OCI image index.
+type manifestList struct {
+	MediaType string              `json:"mediaType"`
+	Manifests []*platformManifest `json:"manifests"`
+}
+
+// manifestPath returns the path to a manifest for the image in the Docker registry API.
+func manifestPath(repo, image, reference string) string {
+	return fmt.Sprintf("api/docker/%s/v2/%s/manifests/%s", repo, image, reference)
+}
+
+// platformManifests captures the platform manifests referenced by the tag of the image.
+//
+// No manifests are returned when the tag is not a manifest list.
+func platformManifests(cli artifactory.ArtifactoryServicesManager, repo, image, tag string) ([]*platformManifest, error) {
+	// send API call to capture the manifest for the tag
+	resp, body, err := apiGetWithHeaders(cli, manifestPath(repo, image, tag), map[string]string{"Accept": manifestAccept})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to capture manifest for %s:%s in %s: %s", image, tag, repo, resp.Status)
+	}
+
+	list := new(manifestList)
+
+	err = json.Unmarshal(body, list)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse manifest for %s:%s in %s: %w", image, tag, repo, err)
+	}
+
+	// fall back to the content type when the manifest does not provide a media type
+	mediaType := list.MediaType
+	if len(mediaType) == 0 {
+		mediaType, _, _ = strings.Cut(resp.Header.Get("Content-Type"), ";")
+	}
+
+	if mediaType != mediaTypeDockerManifestList && mediaType != mediaTypeOCIImageIndex {
+		return nil, nil
+	}
+
+	return list.Manifests, nil
+}
+
+// missingPlatformManifests returns the platform manifests that are not present for the image in the repository.
+func missingPlatformManifests(
+	cli artifactory.ArtifactoryServicesManager,
+	repo, image string,
+	manifests []*platformManifest,
+) ([]*platformManifest, error) {
+	var missing []*platformManifest
+
+	for _, manifest := range manifests {
+		// send API call to capture the platform manifest
+		resp, _, err := apiGetWithHeaders(cli, manifestPath(repo, image, manifest.Digest), map[string]string{"Accept": manifestAccept})
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			missing = append(missing, manifest)
+
+			continue
+		}
+
+		logrus.Tracef("verified platform manifest %s for %s in %s", manifest, image, repo)
+	}
+
+	return missing, nil
+}
+
+// verifyPlatformManifests verifies every platform manifest is present for the image in the repository.
+func verifyPlatformManifests(cli artifactory.ArtifactoryServicesManager, repo, image string, manifests []*platformManifest) error {
+	missing, err := missingPlatformManifests(cli, repo, image, manifests)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		var platforms []string
+
+		for _, manifest := range missing {
+			platforms = append(platforms, manifest.String())
+		}
+
+		return fmt.Errorf("platform manifests missing for %s in %s: %s", image, repo, strings.Join(platforms, ", "))
+	}
+
+	return nil
+}
+
+// promotePlatformManifests copies the platform manifests of a promoted manifest list
+// that are missing from the target image, and verifies every platform manifest is
+// present in the target afterwards.
+func promotePlatformManifests(
+	cli artifactory.ArtifactoryServicesManager,
+	logger *logrus.Entry,
+	payload *services.DockerPromoteParams,
+	manifests []*platformManifest,
+) error {
+	missing, err := missingPlatformManifests(cli, payload.TargetRepo, payload.TargetDockerImage, manifests)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range missing {
+		// platform manifests are stored in a folder named after their digest
+		source := fmt.Sprintf("%s/%s/%s", payload.SourceRepo, payload.SourceDockerImage, manifest.Digest)
+		target := fmt.Sprintf("%s/%s/%s", payload.TargetRepo, payload.TargetDockerImage, manifest.Digest)
+
+		// send API call to copy the platform manifest to the target
+		resp, body, err := apiPost(cli, fmt.Sprintf("api/copy/%s?to=/%s", source, target), nil)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unable to copy platform manifest %s to %s: %s %s", manifest, target, resp.Status, string(body))
+		}
+
+		logger.Infof("Copied platform manifest %s missing from target %s", manifest, payload.TargetRepo)
+	}
+
+	return verifyPlatformManifests(cli, payload.TargetRepo, payload.TargetDockerImage, manifests)
+}
+
+// sourceManifests captures the platform manifests when the source tag is a manifest list.
+func (p *DockerPromote) sourceManifests(
+	cli artifactory.ArtifactoryServicesManager,
+	logger *logrus.Entry,
+	sourceTag string,
+) []*platformManifest {
+	// all tags are promoted when a source tag is not provided
+	if len(sourceTag) == 0 {
+		return nil
+	}
+
+	source := p.SourceRepo
+	if len(source) == 0 {
+		source = p.TargetRepo
+	}
+
+	manifests, err := platformManifests(cli, source, p.DockerRegistry, sourceTag)
+	if err != nil {
+		logger.Warnf("Unable to detect manifest list for %s:%s: %v", p.DockerRegistry, sourceTag, err)
+
+		return nil
+	}
+
+	if len(manifests) > 0 {
+		var platforms []string
+
+		for _, manifest := range manifests {
+			platforms = append(platforms, manifest.String())
+		}
+
+		logger.Infof("Detected manifest list for %s:%s with %d platforms: %s",
+			p.DockerRegistry, sourceTag, len(manifests), strings.Join(platforms, ", "))
+	}
+
+	return manifests
+}
@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_platformManifests(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	c := &Config{
+		URL:      s.URL,
+		Username: mock.Username,
+		Password: mock.Password,
+		Client: &Client{
+			Retries:            3,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	cli, err := c.New()
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name  string
+		image string
+		want  []string
+	}{
+		{
+			name:  "manifest list",
+			image: "multi-arch",
+			want: []string{
+				"linux/amd64 (sha256:7e9b6e7ba2842c91cf49f3e214d04a7a496f8214356f41d81a6e6dcad11f11e3)",
+				"linux/arm64/v8 (sha256:084c3bdd1271adc754e2c5f6ba7046f1a2c099597dbd9643c78e1aa5bec5a5b2)",
+			},
+		},
+		{
+			name:  "image manifest",
+			image: "hello-world",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := platformManifests(*cli, "docker", test.image, "latest")
+			if err != nil {
+				t.Errorf("platformManifests returned err: %v", err)
+			}
+
+			if len(got) != len(test.want) {
+				t.Fatalf("platformManifests returned %d manifests, want %d", len(got), len(test.want))
+			}
+
+			for i, manifest := range got {
+				if manifest.String() != test.want[i] {
+					t.Errorf("platformManifests is %s, want %s", manifest, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_ManifestList(t *testing.T) {
+	// setup types
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	handler := mock.Handlers()
+
+	// record the properties requests sent to the mock server
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/storage/") {
+			mu.Lock()
+			requests = append(requests, r.URL.Path)
+			mu.Unlock()
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			SourceRepo:      "docker-dev",
+			TargetRepo:      "docker-prod",
+			DockerRegistry:  "multi-arch",
+			SourceTag:       "latest",
+			TargetTags:      []string{"stable"},
+			PromoteProperty: true,
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := []string{
+		"/api/storage/docker-prod/multi-arch/stable",
+		"/api/storage/docker-prod/multi-arch/sha256:7e9b6e7ba2842c91cf49f3e214d04a7a496f8214356f41d81a6e6dcad11f11e3",
+		"/api/storage/docker-prod/multi-arch/sha256:084c3bdd1271adc754e2c5f6ba7046f1a2c099597dbd9643c78e1aa5bec5a5b2",
+	}
+
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Exec set properties on %v, want %v", requests, want)
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_ManifestList_Missing(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			SourceRepo:     "docker-dev",
+			TargetRepo:     "docker-missing",
+			DockerRegistry: "multi-arch",
+			SourceTag:      "latest",
+			TargetTags:     []string{"stable"},
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+
+	if err != nil && !strings.Contains(err.Error(), "linux/arm64/v8") {
+		t.Errorf("Exec err is %v, want missing platforms", err)
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_ManifestList_SourceMissing(t *testing.T) {
+	// setup types
+	s, requests := requestServer(t)
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			SourceRepo:     "docker-missing",
+			TargetRepo:     "docker-prod",
+			DockerRegistry: "multi-arch",
+			SourceTag:      "latest",
+			TargetTags:     []string{"stable"},
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+
+	// the image is not promoted when a platform manifest is missing from the source
+	for _, request := range requests() {
+		if strings.HasSuffix(request, "/promote") {
+			t.Errorf("Exec sent %s with a platform manifest missing from the source", request)
+		}
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_ManifestList_Copy(t *testing.T) {
+	// setup types
+	var (
+		mu     sync.Mutex
+		copied []string
+	)
+
+	handler := mock.Handlers()
+
+	// platform manifests are missing from the target until they are copied
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/copy/") {
+			copied = append(copied, r.URL.Path+"?"+r.URL.RawQuery)
+		}
+
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/docker-prod/v2/multi-arch/manifests/sha256:") {
+			digest := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+			found := false
+
+			for _, c := range copied {
+				if strings.HasSuffix(c, digest) {
+					found = true
+				}
+			}
+
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			SourceRepo:     "docker-dev",
+			TargetRepo:     "docker-prod",
+			DockerRegistry: "multi-arch",
+			SourceTag:      "latest",
+			TargetTags:     []string{"stable"},
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := []string{
+		"/api/copy/docker-dev/multi-arch/sha256:7e9b6e7ba2842c91cf49f3e214d04a7a496f8214356f41d81a6e6dcad11f11e3?to=/docker-prod/multi-arch/sha256:7e9b6e7ba2842c91cf49f3e214d04a7a496f8214356f41d81a6e6dcad11f11e3",
+		"/api/copy/docker-dev/multi-arch/sha256:084c3bdd1271adc754e2c5f6ba7046f1a2c099597dbd9643c78e1aa5bec5a5b2?to=/docker-prod/multi-arch/sha256:084c3bdd1271adc754e2c5f6ba7046f1a2c099597dbd9643c78e1aa5bec5a5b2",
+	}
+
+	if strings.Join(copied, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Exec copied %v, want %v", copied, want)
+	}
+}
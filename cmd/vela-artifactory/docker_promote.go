@@ -5,10 +5,12 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/sirupsen/logrus"
 )
 
@@ -26,25 +28,202 @@ type DockerPromote struct {
 	TargetDockerRegistry string
 	// SourceTag is the name of image to promote (promotes all tags if empty)
 	SourceTag string
-	// TargetTags are the target tags to assign to the image after promotion
+	// TargetTags are the target tags to assign to the image after promotion (supports templates)
 	TargetTags []string
+	// TagRules are the rules for expanding a semantic version source tag into target tags
+	TagRules []string
+	// TagFilter is the pattern for matching the tags of the image to promote
+	TagFilter string
+	// TagFilterType is the type of pattern for the tag filter (glob, regex or semver)
+	TagFilterType string
+	// KeepTagName is a flag to set to keep the name of the source tag in the target
+	KeepTagName bool
+	// Images are the images to promote in a single step
+	Images []*DockerImage
+	// RawImages is raw input of images provided for plugin
+	RawImages string
+	// Concurrency is the maximum number of images to promote at the same time
+	Concurrency int
+	// Rollback is a flag to set to revert promoted images when promoting an image fails
+	Rollback bool
+	// DryRun is a flag to set to log the planned promotions without promoting images or setting properties
+	DryRun bool
 	// Copy is a flag to set to copy instead of moving the image (default: true)
 	Copy bool
 	// PromoteProperty is an optional value to set an item property to add a promoted date.
 	PromoteProperty bool
+	// Props are properties to set on the image after promotion (supports templates)
+	Props []*Prop
+	// RawProps is raw input of properties provided for plugin
+	RawProps string
+	// Assert are the properties the source image(s) must or must not have to be promoted
+	Assert PropAssertion
+}
+
+// tagResult represents the outcome of promoting a source tag.
+type tagResult struct {
+	// SourceTag is the tag of the image that was promoted
+	SourceTag string
+	// Promoted are the target tags the source tag was promoted to
+	Promoted []string
+	// Planned are the target tags the source tag would be promoted to in a dry run
+	Planned []string
+	// Err is the error that stopped the promotion of the source tag
+	Err error
+}
+
+// promotion represents a source tag and the target tags it is promoted to.
+type promotion struct {
+	// SourceTag is the tag of the image to promote
+	SourceTag string
+	// TargetTags are the tags to assign to the image after promotion
+	TargetTags []string
 }
 
 // Exec formats and runs the commands for uploading artifacts in Artifactory.
 func (p *DockerPromote) Exec(cli artifactory.ArtifactoryServicesManager) error {
 	logrus.Trace("running docker-promote with provided configuration")
 
-	var payloads []*services.DockerPromoteParams
+	// promote every image when a list of images is provided
+	if len(p.Images) > 0 {
+		return p.execImages(cli)
+	}
+
+	logger := actionLogger(dockerPromoteAction, p.TargetRepo)
+
+	// resolve every source and target tag before promoting
+	promotions, err := p.plan(cli, newBuildMetadata())
+	if err != nil {
+		return err
+	}
+
+	results, err := p.promote(cli, logger, promotions, nil)
+
+	// report the outcome for every tag matched by the filter
+	if len(p.TagFilter) > 0 {
+		p.report(logger, results)
+	}
+
+	return err
+}
+
+// plan resolves the source tags of the image and the target tags for each of them.
+func (p *DockerPromote) plan(cli artifactory.ArtifactoryServicesManager, build *BuildMetadata) ([]*promotion, error) {
+	// capture the source tags to promote
+	sourceTags, err := p.SourceTags(cli)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify the properties of the source tags before promoting any of them
+	if p.Assert.Enabled() {
+		err = p.assertSourceTags(cli, sourceTags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	promotions := make([]*promotion, 0, len(sourceTags))
+
+	for _, sourceTag := range sourceTags {
+		// resolve the target tags from the templates and tag rules
+		targetTags, err := p.ResolveTargetTags(sourceTag, build)
+		if err != nil {
+			return nil, err
+		}
 
-	if len(p.TargetTags) == 0 {
+		promotions = append(promotions, &promotion{SourceTag: sourceTag, TargetTags: targetTags})
+	}
+
+	return promotions, nil
+}
+
+// assertSourceTags verifies the manifest of every source tag has the
+// required and none of the forbidden properties.
+func (p *DockerPromote) assertSourceTags(cli artifactory.ArtifactoryServicesManager, sourceTags []string) error {
+	source := p.SourceRepo
+	if len(source) == 0 {
+		source = p.TargetRepo
+	}
+
+	logger := actionLogger(dockerPromoteAction, p.TargetRepo).WithField("artifact", p.DockerRegistry)
+
+	params := make([]*utils.CommonParams, 0, len(sourceTags))
+
+	for _, sourceTag := range sourceTags {
+		// every tag of the image is promoted when a source tag is not provided
+		if len(sourceTag) == 0 {
+			sourceTag = "*"
+		}
+
+		// the manifest of a tag is stored as manifest.json, or list.manifest.json for a manifest list
+		params = append(params, &utils.CommonParams{
+			Pattern: fmt.Sprintf("%s/%s/%s/*manifest.json", source, p.DockerRegistry, sourceTag),
+		})
+	}
+
+	return p.Assert.Assert(cli, logger, params...)
+}
+
+// promote promotes each source tag to its target tags and returns the outcome for each.
+//
+// When stop is provided, the remaining source tags are not promoted once
+// it returns true or a source tag fails to promote.
+func (p *DockerPromote) promote(
+	cli artifactory.ArtifactoryServicesManager,
+	logger *logrus.Entry,
+	promotions []*promotion,
+	stop func() bool,
+) ([]*tagResult, error) {
+	var (
+		results []*tagResult
+		failed  []error
+	)
+
+	for _, promotion := range promotions {
+		if stop != nil && stop() {
+			break
+		}
+
+		result := p.promoteTag(cli, logger, promotion.SourceTag, promotion.TargetTags)
+
+		results = append(results, result)
+
+		if result.Err != nil {
+			failed = append(failed, result.Err)
+
+			// avoid promoting further tags once a tag has failed when the promotion can be stopped
+			if stop != nil {
+				break
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return results, fmt.Errorf("unable to promote %d of %d tags: %w", len(failed), len(results), failed[0])
+	}
+
+	return results, nil
+}
+
+// promoteTag promotes the source tag to each of the resolved target tags.
+func (p *DockerPromote) promoteTag(
+	cli artifactory.ArtifactoryServicesManager,
+	logger *logrus.Entry,
+	sourceTag string,
+	targetTags []string,
+) *tagResult {
+	result := &tagResult{SourceTag: sourceTag}
+
+	if len(targetTags) == 0 {
 		logrus.Trace("no tags to promote")
+	} else {
+		logger.Infof("Resolved target tags: %s", strings.Join(targetTags, ", "))
 	}
 
-	for _, t := range p.TargetTags {
+	var payloads []*services.DockerPromoteParams
+
+	for _, t := range targetTags {
 		// avoid assigning parameters via constructor to ensure promote endpoint is constructed properly
 		params := services.NewDockerPromoteParams(
 			"",
@@ -69,7 +248,7 @@ func (p *DockerPromote) Exec(cli artifactory.ArtifactoryServicesManager) error {
 			params.TargetDockerImage = params.SourceDockerImage
 		}
 
-		params.SourceTag = p.SourceTag
+		params.SourceTag = sourceTag
 		params.TargetTag = t
 		params.Copy = p.Copy
 
@@ -77,115 +256,116 @@ func (p *DockerPromote) Exec(cli artifactory.ArtifactoryServicesManager) error {
 
 		pretty, err := json.MarshalIndent(params, "", "  ")
 		if err != nil {
-			return err
+			result.Err = err
+
+			return result
 		}
 
 		logrus.Tracef("created payload for target tag %s: %s", t, string(pretty))
 	}
 
-	for _, payload := range payloads {
-		logrus.Infof("Promoting tag %s to target %s", payload.GetSourceTag(), payload.GetTargetTag())
+	// capture the platform manifests when the source tag is a manifest list
+	var manifests []*platformManifest
+	if len(payloads) > 0 {
+		manifests = p.sourceManifests(cli, logger, sourceTag)
+	}
 
-		err := cli.PromoteDocker(*payload)
+	// verify every platform manifest of a manifest list is present in the source before promoting
+	if len(manifests) > 0 {
+		err := verifyPlatformManifests(cli, payloads[0].SourceRepo, payloads[0].SourceDockerImage, manifests)
 		if err != nil {
-			return err
+			result.Err = err
+
+			return result
 		}
+	}
 
-		// recursively assign promoted_on property based on plugin configuration
-		if p.PromoteProperty {
-			logrus.Infof("Setting promote properties for %s/%s/%s",
-				payload.TargetRepo,
-				payload.TargetDockerImage,
-				payload.TargetTag)
-
-			// setup base property params
-			ts := time.Now().UTC().Format(time.RFC3339)
-			promotedOnProperty := fmt.Sprintf("promoted_on=%s", ts)
-			propsParams := services.NewPropsParams()
-			propsParams.Props = promotedOnProperty
-
-			// setup base search params
-			searchParams := services.NewSearchParams()
-			searchParams.Recursive = true
-			searchParams.IncludeDirs = true
-
-			// this is required to set properties on the image's folder artifact
-			imageFolderSearchPattern := fmt.Sprintf(
-				"%s/%s/%s",
-				payload.TargetRepo,
-				payload.TargetDockerImage,
-				payload.TargetTag,
-			)
-
-			logrus.Tracef("searching files using pattern %s", imageFolderSearchPattern)
-
-			searchParams.Pattern = imageFolderSearchPattern
-
-			imageFolderReader, err := cli.SearchFiles(searchParams)
-			if err != nil {
-				return err
+	for _, payload := range payloads {
+		start := time.Now()
+		tagLogger := logger.WithField("artifact", fmt.Sprintf("%s:%s", payload.TargetDockerImage, payload.TargetTag))
+
+		// the client dry run does not cover docker promotion, so only log the planned promotion
+		if p.DryRun {
+			tagLogger.Infof("  [dry run] %s:%s -> %s:%s",
+				payload.SourceDockerImage, payload.SourceTag, payload.TargetDockerImage, payload.TargetTag)
+
+			// log the properties that would be assigned to the promoted image
+			if p.PromoteProperty || len(p.Props) > 0 {
+				err := p.setPromoteProperties(cli, tagLogger, payload, manifests)
+				if err != nil {
+					result.Err = err
+
+					return result
+				}
 			}
 
-			defer imageFolderReader.Close()
-
-			// assign the files found to be used in SetProps
-			propsParams.Reader = imageFolderReader
+			result.Planned = append(result.Planned, payload.TargetTag)
 
-			logrus.Tracef("assigning property [%s] to %d matched images", promotedOnProperty, len(imageFolderReader.GetFilesPaths()))
-
-			imageFolderSuccess, err := cli.SetProps(propsParams)
-			if err != nil {
-				return err
-			}
-
-			// setup base search params
-			searchParams = services.NewSearchParams()
-			searchParams.Recursive = true
-			searchParams.IncludeDirs = true
+			continue
+		}
 
-			// this is required to set properties on the image's folder artifact
-			imageContentsSearchPattern := fmt.Sprintf(
-				"%s/%s/%s/*",
-				payload.TargetRepo,
-				payload.TargetDockerImage,
-				payload.TargetTag,
-			)
+		tagLogger.Infof("Promoting tag %s to target %s", payload.GetSourceTag(), payload.GetTargetTag())
 
-			logrus.Tracef("searching files using pattern %s", imageContentsSearchPattern)
+		err := cli.PromoteDocker(*payload)
+		if err != nil {
+			result.Err = err
 
-			searchParams.Pattern = imageContentsSearchPattern
+			return result
+		}
 
-			imageContentsReader, err := cli.SearchFiles(searchParams)
+		// promote every platform manifest of a manifest list missing from the target
+		if len(manifests) > 0 {
+			err = promotePlatformManifests(cli, tagLogger, payload, manifests)
 			if err != nil {
-				return err
-			}
-
-			defer imageContentsReader.Close()
+				result.Err = err
 
-			// assign the files found to be used in SetProps
-			propsParams.Reader = imageContentsReader
+				return result
+			}
 
-			logrus.Tracef("assigning property [%s] to %d matched images", promotedOnProperty, len(imageContentsReader.GetFilesPaths()))
+			tagLogger.Infof("Verified %d platform manifests promoted to target %s", len(manifests), payload.TargetRepo)
+		}
 
-			imageContentsSuccess, err := cli.SetProps(propsParams)
+		// recursively assign promote properties based on plugin configuration
+		if p.PromoteProperty || len(p.Props) > 0 {
+			err = p.setPromoteProperties(cli, tagLogger, payload, manifests)
 			if err != nil {
-				return err
-			}
+				result.Err = err
 
-			totalSuccess := imageFolderSuccess + imageContentsSuccess
-			if totalSuccess > 0 {
-				logrus.Infof("Successfully assigned property [%s] to %d image files.", promotedOnProperty, totalSuccess)
-			} else {
-				logrus.Info("Promote properties not assigned to any images.")
+				return result
 			}
 		}
 
-		logrus.Infof("Promotion ended successfully for tag %s promoted to target tag %s",
+		result.Promoted = append(result.Promoted, payload.TargetTag)
+
+		withDuration(tagLogger, start).Infof("Promotion ended successfully for tag %s promoted to target tag %s",
 			payload.SourceTag,
 			payload.TargetTag)
 	}
 
-	return nil
+	return result
+}
+
+// report logs the outcome of promoting each source tag.
+func (p *DockerPromote) report(logger *logrus.Entry, results []*tagResult) {
+	logger.Infof("Promotion report for %d tags matching %s:", len(results), p.TagFilter)
+
+	for _, result := range results {
+		entry := logger.WithField("artifact", fmt.Sprintf("%s:%s", p.DockerRegistry, result.SourceTag))
+
+		if result.Err != nil {
+			entry.Errorf("  [failed] %s: %v", result.SourceTag, result.Err)
+
+			continue
+		}
+
+		if len(result.Planned) > 0 {
+			entry.Infof("  [dry run] %s -> %s", result.SourceTag, strings.Join(result.Planned, ", "))
+
+			continue
+		}
+
+		entry.Infof("  [promoted] %s -> %s", result.SourceTag, strings.Join(result.Promoted, ", "))
+	}
 }
 
 // Validate verifies the Promote is properly configured.
@@ -195,10 +375,51 @@ func (p *DockerPromote) Validate() error {
 		return fmt.Errorf("no target repository provided")
 	}
 
+	// serialize provided images and properties into expected type
+	err := p.Unmarshal()
+	if err != nil {
+		return err
+	}
+
+	// verify the properties are valid
+	err = p.validateProps()
+	if err != nil {
+		return err
+	}
+
+	// verify the property assertions are valid
+	err = p.Assert.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid docker-promote assertion provided: %w", err)
+	}
+
+	// verify the images are valid
+	if len(p.Images) > 0 {
+		return p.validateImages()
+	}
+
 	// verify a docker registry is provided
 	if len(p.DockerRegistry) == 0 {
 		return fmt.Errorf("no docker repository provided")
 	}
 
-	return nil
+	return p.validateTags()
+}
+
+// validateTags verifies the source and target tags are properly configured.
+func (p *DockerPromote) validateTags() error {
+	// verify the target tags are valid templates
+	err := p.validateTargetTags()
+	if err != nil {
+		return err
+	}
+
+	// verify the tag filter is valid
+	err = p.validateTagFilter()
+	if err != nil {
+		return err
+	}
+
+	// verify the tag rules are valid for the source tag
+	return p.validateTagRules()
 }
@@ -3,7 +3,9 @@
 package main
 
 import (
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
@@ -75,6 +77,77 @@ func TestArtifactory_DockerPromote_Exec_Error(t *testing.T) {
 	}
 }
 
+func TestArtifactory_DockerPromote_Exec_DryRun(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		promote *DockerPromote
+	}{
+		{
+			name: "image",
+			promote: &DockerPromote{
+				TargetRepo:      "docker",
+				DockerRegistry:  "github/octocat",
+				SourceTag:       "latest",
+				TargetTags:      []string{"stable", "1.0.0"},
+				PromoteProperty: true,
+			},
+		},
+		{
+			name: "images",
+			promote: &DockerPromote{
+				TargetRepo: "docker",
+				TargetTags: []string{"stable"},
+				Rollback:   true,
+				Images: []*DockerImage{
+					{Image: "github/api", Tag: "1.0.0"},
+					{Image: "github/web", Tag: "1.0.0"},
+				},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, requests := requestServer(t)
+
+			test.promote.DryRun = true
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "docker-promote",
+					DryRun:   true,
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				DockerPromote: test.promote,
+			}
+
+			err := p.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = p.Exec()
+			if err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			for _, request := range requests() {
+				if strings.HasSuffix(request, "/promote") || strings.HasPrefix(request, http.MethodPut) {
+					t.Errorf("Exec sent %s in dry run", request)
+				}
+			}
+		})
+	}
+}
+
 func TestArtifactory_DockerPromote_Validate(t *testing.T) {
 	// setup types
 	p := &DockerPromote{
@@ -111,3 +184,79 @@ func TestArtifactory_DockerPromote_Validate_DockerRepo(t *testing.T) {
 		t.Errorf("Validate should have returned err")
 	}
 }
+
+func TestArtifactory_DockerPromote_Validate_TagRules(t *testing.T) {
+	// setup types
+	p := &DockerPromote{
+		TargetRepo:     "docker",
+		DockerRegistry: "github/octocat",
+		SourceTag:      "v1.4.2",
+		TagRules:       []string{"major", "foo"},
+	}
+
+	err := p.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestArtifactory_DockerPromote_Validate_TargetTags(t *testing.T) {
+	// setup types
+	p := &DockerPromote{
+		TargetRepo:     "docker",
+		DockerRegistry: "github/octocat",
+		TargetTags:     []string{"{{ .CommitShort"},
+	}
+
+	err := p.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestArtifactory_DockerPromote_Validate_TagFilter(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		p    *DockerPromote
+	}{
+		{
+			name: "with source tag",
+			p: &DockerPromote{
+				TargetRepo:     "docker",
+				DockerRegistry: "github/octocat",
+				SourceTag:      "latest",
+				TagFilter:      "1.*",
+				KeepTagName:    true,
+			},
+		},
+		{
+			name: "without target tags",
+			p: &DockerPromote{
+				TargetRepo:     "docker",
+				DockerRegistry: "github/octocat",
+				TagFilter:      "1.*",
+			},
+		},
+		{
+			name: "invalid semver constraint",
+			p: &DockerPromote{
+				TargetRepo:     "docker",
+				DockerRegistry: "github/octocat",
+				TagFilter:      ">=foo",
+				TagFilterType:  "semver",
+				KeepTagName:    true,
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.p.Validate()
+			if err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+		})
+	}
+}
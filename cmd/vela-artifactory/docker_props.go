@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	json "github.com/ghodss/yaml"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// promotedOnProperty is the property set with the time an image was promoted.
+const promotedOnProperty = "promoted_on"
+
+// promoteTemplateData represents the values available to promote property templates.
+type promoteTemplateData struct {
+	*BuildMetadata
+
+	// SourceRepo is the repository the image was promoted from
+	SourceRepo string
+	// SourceImage is the image that was promoted
+	SourceImage string
+	// SourceTag is the tag of the image that was promoted
+	SourceTag string
+	// TargetRepo is the repository the image was promoted to
+	TargetRepo string
+	// TargetImage is the image the image was promoted to
+	TargetImage string
+	// TargetTag is the tag the image was promoted to
+	TargetTag string
+}
+
+// Unmarshal captures the provided images and properties
+// and serializes them into their expected form.
+func (p *DockerPromote) Unmarshal() error {
+	if len(p.RawImages) > 0 {
+		logrus.Trace("unmarshaling raw images")
+
+		// serialize raw images into expected Images type
+		err := json.Unmarshal([]byte(p.RawImages), &p.Images)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal docker-promote images: %w", err)
+		}
+	}
+
+	if len(p.RawProps) > 0 {
+		logrus.Trace("unmarshaling raw props")
+
+		// serialize raw properties into expected Props type
+		err := json.Unmarshal([]byte(p.RawProps), &p.Props)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal docker-promote props: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateProps verifies the promote properties are properly configured.
+func (p *DockerPromote) validateProps() error {
+	err := validatePropTemplates(p.Props)
+	if err != nil {
+		return fmt.Errorf("invalid docker-promote prop provided: %w", err)
+	}
+
+	err = validateSetMode(p.Props)
+	if err != nil {
+		return fmt.Errorf("invalid docker-promote prop provided: %w", err)
+	}
+
+	return nil
+}
+
+// validatePropTemplates verifies the properties and their templates are valid.
+func validatePropTemplates(props []*Prop) error {
+	for _, prop := range props {
+		// verify the property is valid
+		err := prop.Validate()
+		if err != nil {
+			return err
+		}
+
+		// verify the property values are valid templates
+		for _, value := range append([]string{prop.Value}, prop.Values...) {
+			_, err := parseTemplate(prop.Name, value)
+			if err != nil {
+				return fmt.Errorf("prop %s: %w", prop.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// renderProps renders the property templates with the data and
+// returns the query string for each of the rendered properties.
+func renderProps(props []*Prop, data any) ([]string, error) {
+	var rendered []string
+
+	for _, prop := range props {
+		r, err := renderProp(prop, data)
+		if err != nil {
+			return nil, err
+		}
+
+		rendered = append(rendered, r.String())
+	}
+
+	return rendered, nil
+}
+
+// renderProp renders the property templates with the data, or reads
+// the value from the property's file, and returns the rendered property.
+func renderProp(prop *Prop, data any) (*Prop, error) {
+	r := &Prop{Name: prop.Name, Mode: prop.Mode}
+
+	// values read from a file are used as-is
+	if len(prop.File) > 0 {
+		value, err := prop.fileValue()
+		if err != nil {
+			return nil, err
+		}
+
+		r.Value = value
+
+		return r, r.validatePropValues()
+	}
+
+	value, err := renderTemplate(prop.Name, prop.Value, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render prop %s: %w", prop.Name, err)
+	}
+
+	r.Value = value
+
+	for _, v := range prop.Values {
+		value, err := renderTemplate(prop.Name, v, data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render prop %s: %w", prop.Name, err)
+		}
+
+		r.Values = append(r.Values, value)
+	}
+
+	// verify the rendered values can be separated from the next value or property
+	return r, r.validatePropValues()
+}
+
+// promoteProps returns the properties to set on the promoted image.
+func (p *DockerPromote) promoteProps(payload *services.DockerPromoteParams) (string, error) {
+	data := &promoteTemplateData{
+		BuildMetadata: newBuildMetadata(),
+		SourceRepo:    payload.SourceRepo,
+		SourceImage:   payload.SourceDockerImage,
+		SourceTag:     payload.SourceTag,
+		TargetRepo:    payload.TargetRepo,
+		TargetImage:   payload.TargetDockerImage,
+		TargetTag:     payload.TargetTag,
+	}
+
+	var props []string
+
+	if p.PromoteProperty {
+		props = append(props, fmt.Sprintf("%s=%s", promotedOnProperty, time.Now().UTC().Format(time.RFC3339)))
+	}
+
+	// render each property using the build and promotion information
+	rendered, err := renderProps(p.Props, data)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(append(props, rendered...), ";"), nil
+}
+
+// setPromoteProperties assigns the promote properties to the promoted image.
+//
+// The properties are set on the image's folder and everything within it
+// using a single recursive request to the Artifactory storage API. When
+// the image is a manifest list, the properties are also set on the folder
+// for every platform manifest referenced by it.
+func (p *DockerPromote) setPromoteProperties(
+	cli artifactory.ArtifactoryServicesManager,
+	tagLogger *logrus.Entry,
+	payload *services.DockerPromoteParams,
+	manifests []*platformManifest,
+) error {
+	image := fmt.Sprintf("%s/%s/%s", payload.TargetRepo, payload.TargetDockerImage, payload.TargetTag)
+
+	tagLogger.Infof("Setting promote properties for %s", image)
+
+	props, err := p.promoteProps(payload)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := utils.ParseProperties(props)
+	if err != nil {
+		return fmt.Errorf("unable to parse promote properties: %w", err)
+	}
+
+	// paths are the image folders relative to the target repository
+	paths := []string{fmt.Sprintf("%s/%s", payload.TargetDockerImage, payload.TargetTag)}
+
+	// platform manifests are stored in a folder named after their digest
+	for _, manifest := range manifests {
+		paths = append(paths, fmt.Sprintf("%s/%s", payload.TargetDockerImage, manifest.Digest))
+	}
+
+	folders := make([]string, 0, len(paths))
+
+	for _, folder := range paths {
+		folders = append(folders, fmt.Sprintf("%s/%s", payload.TargetRepo, folder))
+	}
+
+	// the properties are set through raw API calls the client dry run does not cover
+	if p.DryRun {
+		for _, folder := range folders {
+			tagLogger.WithField("artifact", folder).Infof("  [dry run] %s", folder)
+		}
+
+		tagLogger.Infof("Dry run enabled, skipping properties [%s] for %d image folder(s)", props, len(folders))
+
+		return nil
+	}
+
+	for _, folder := range folders {
+		err := setFolderProperties(cli, folder, parsed.ToEncodedString(true))
+		if err != nil {
+			return err
+		}
+
+		logrus.Tracef("assigned properties [%s] to %s and the items within it", props, folder)
+	}
+
+	// count the items within the folders that were assigned the properties
+	count, err := countFolderItems(cli, payload.TargetRepo, paths)
+	if err != nil {
+		tagLogger.Warnf("Assigned properties [%s] to %d image folder(s), unable to count the items within them: %v", props, len(folders), err)
+
+		return nil
+	}
+
+	tagLogger.WithField("success", count).Infof("Successfully assigned properties [%s] to %d item(s) in %d image folder(s).", props, count, len(folders))
+
+	return nil
+}
+
+// countFolderItems returns the number of items assigned properties by a recursive
+// request to each folder of the repository, including the folder itself, using a
+// single AQL search.
+func countFolderItems(cli artifactory.ArtifactoryServicesManager, repo string, folders []string) (int, error) {
+	matches := make([]map[string]any, 0, len(folders)*3)
+
+	for _, folder := range folders {
+		matches = append(matches,
+			// match the folder itself
+			map[string]any{"path": path.Dir(folder), "name": path.Base(folder)},
+			// match the items directly within the folder
+			map[string]any{"path": folder},
+			// match the items within the sub-directories of the folder
+			map[string]any{"path": map[string]string{"$match": folder + "/*"}},
+		)
+	}
+
+	query := map[string]any{
+		"repo": repo,
+		"type": "any",
+		"$or":  matches,
+	}
+
+	items, err := aqlSearch(cli, query, "repo", "path", "name")
+	if err != nil {
+		return 0, err
+	}
+
+	return len(items), nil
+}
+
+// setFolderProperties recursively assigns the encoded properties to the
+// folder and everything within it using the Artifactory storage API.
+func setFolderProperties(cli artifactory.ArtifactoryServicesManager, folder, props string) error {
+	// send API call to recursively set properties on the folder
+	resp, body, err := apiPut(cli, fmt.Sprintf("api/storage/%s?properties=%s&recursive=1", folder, props))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to set promote properties on %s: %s %s", folder, resp.Status, string(body))
+	}
+
+	return nil
+}
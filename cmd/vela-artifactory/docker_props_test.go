@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_DockerPromote_Exec_Props(t *testing.T) {
+	// setup types
+	t.Setenv("VELA_BUILD_LINK", "https://vela.example.com/github/octocat/42")
+
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	handler := mock.Handlers()
+
+	// record the properties requests sent to the mock server
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/storage/") {
+			mu.Lock()
+			requests = append(requests, r.URL.String())
+			mu.Unlock()
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			TargetRepo:      "docker",
+			DockerRegistry:  "github/octocat",
+			SourceTag:       "latest",
+			TargetTags:      []string{"stable"},
+			PromoteProperty: true,
+			RawProps: `
+- name: vela.build
+  value: "{{ .BuildLink }}"
+- name: source
+  values: [ "{{ .SourceImage }}", "{{ .SourceTag }}" ]
+`,
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	// properties are set with a single recursive request
+	if len(requests) != 1 {
+		t.Fatalf("Exec sent %d properties requests, want 1", len(requests))
+	}
+
+	got := requests[0]
+
+	for _, want := range []string{
+		"/api/storage/docker/github/octocat/stable?",
+		"recursive=1",
+		"promoted_on=",
+		"vela.build=https",
+		"source=github",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Exec request is %s, want it to contain %s", got, want)
+		}
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_Props_DryRun(t *testing.T) {
+	// setup types
+	s, requests := requestServer(t)
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			DryRun:   true,
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			TargetRepo:      "docker",
+			DockerRegistry:  "github/octocat",
+			SourceTag:       "latest",
+			TargetTags:      []string{"stable"},
+			PromoteProperty: true,
+			DryRun:          true,
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	for _, request := range requests() {
+		if strings.HasPrefix(request, http.MethodPut+" /api/storage/") {
+			t.Errorf("Exec sent %s in dry run", request)
+		}
+	}
+}
+
+func TestArtifactory_DockerPromote_promoteProps(t *testing.T) {
+	// setup types
+	t.Setenv("VELA_BUILD_COMMIT", "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d")
+	t.Setenv("VELA_BUILD_AUTHOR", "octocat")
+
+	p := &DockerPromote{
+		Props: []*Prop{
+			{Name: "vela.commit", Value: "{{ .CommitShort }}"},
+			{Name: "vela.author", Value: "{{ .Author }}"},
+			{Name: "promoted.from", Values: []string{"{{ .SourceRepo }}", "{{ .SourceTag }}"}},
+		},
+	}
+
+	payload := &services.DockerPromoteParams{
+		SourceRepo: "docker-dev",
+		SourceTag:  "latest",
+	}
+
+	got, err := p.promoteProps(payload)
+	if err != nil {
+		t.Errorf("promoteProps returned err: %v", err)
+	}
+
+	want := "vela.commit=7fd1a60b;vela.author=octocat;promoted.from=docker-dev,latest"
+
+	if got != want {
+		t.Errorf("promoteProps is %s, want %s", got, want)
+	}
+}
+
+func TestArtifactory_DockerPromote_Validate_Props(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		rawProps string
+	}{
+		{name: "invalid raw props", rawProps: "[{ name: "},
+		{name: "missing value", rawProps: `[{"name": "foo"}]`},
+		{name: "invalid template", rawProps: `[{"name": "foo", "value": "{{ .Commit"}]`},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &DockerPromote{
+				TargetRepo:     "docker",
+				DockerRegistry: "github/octocat",
+				RawProps:       test.rawProps,
+			}
+
+			err := p.Validate()
+			if err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+		})
+	}
+}
+
+func TestArtifactory_countFolderItems(t *testing.T) {
+	// setup types
+	var query string
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		query = string(body)
+
+		_, _ = w.Write([]byte(`{"results": [
+			{"repo": "docker", "path": "github/octocat", "name": "stable"},
+			{"repo": "docker", "path": "github/octocat/stable", "name": "manifest.json"},
+			{"repo": "docker", "path": "github/octocat/stable", "name": "sha256__a3ed95caeb02"}
+		]}`))
+	}))
+	defer s.Close()
+
+	cli, err := (&Config{
+		URL:      s.URL,
+		Username: mock.Username,
+		Password: mock.Password,
+		Client: &Client{
+			Retries:            3,
+			RetryWaitMilliSecs: 1,
+		},
+	}).New()
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	got, err := countFolderItems(*cli, "docker", []string{"github/octocat/stable"})
+	if err != nil {
+		t.Errorf("countFolderItems returned err: %v", err)
+	}
+
+	if got != 3 {
+		t.Errorf("countFolderItems is %d, want 3", got)
+	}
+
+	for _, want := range []string{
+		`"repo":"docker"`,
+		`{"name":"stable","path":"github/octocat"}`,
+		`{"path":"github/octocat/stable"}`,
+		`{"path":{"$match":"github/octocat/stable/*"}}`,
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("countFolderItems query is %s, want it to contain %s", query, want)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// tagRuleMajor generates the major version tag (e.g. 1) from the source tag.
+	tagRuleMajor = "major"
+	// tagRuleMinor generates the major and minor version tag (e.g. 1.4) from the source tag.
+	tagRuleMinor = "minor"
+	// tagRulePatch generates the full version tag (e.g. 1.4.2) from the source tag.
+	tagRulePatch = "patch"
+	// tagRuleLatest generates the latest tag when the source tag is not a prerelease.
+	tagRuleLatest = "latest"
+)
+
+// tagRules represents the valid rules for expanding a semantic version source tag.
+var tagRules = []string{tagRuleMajor, tagRuleMinor, tagRulePatch, tagRuleLatest}
+
+const (
+	// tagFilterGlob matches source tags using a glob pattern (e.g. 1.*).
+	tagFilterGlob = "glob"
+	// tagFilterRegex matches source tags using a regular expression (e.g. ^1\.[0-9]+\.0$).
+	tagFilterRegex = "regex"
+	// tagFilterSemver matches source tags using a semantic version constraint (e.g. >=1.2.0 <2).
+	tagFilterSemver = "semver"
+)
+
+// tagFilterTypes represents the valid types of filters for matching source tags.
+var tagFilterTypes = []string{tagFilterGlob, tagFilterRegex, tagFilterSemver}
+
+// dockerTags represents the tags for an image in a Docker repository.
+//
+// https://jfrog.com/help/r/jfrog-rest-apis/list-docker-tags
+type dockerTags struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// dockerTagRe matches a valid Docker image tag.
+//
+// https://docs.docker.com/reference/cli/docker/image/tag/
+var dockerTagRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// tagTemplateData represents the values available to target tag templates.
+type tagTemplateData struct {
+	*BuildMetadata
+
+	// SourceTag is the tag of the image being promoted
+	SourceTag string
+	// Major is the major version of the source tag
+	Major uint64
+	// Minor is the minor version of the source tag
+	Minor uint64
+	// Patch is the patch version of the source tag
+	Patch uint64
+	// Prerelease is the prerelease version of the source tag
+	Prerelease string
+}
+
+// validateTagRules verifies the tag rules are supported and the source tag is a semantic version.
+func (p *DockerPromote) validateTagRules() error {
+	if len(p.TagRules) == 0 {
+		return nil
+	}
+
+	for _, rule := range p.TagRules {
+		if !slices.Contains(tagRules, strings.ToLower(rule)) {
+			return fmt.Errorf("invalid tag rule %s provided (valid rules: %s)", rule, strings.Join(tagRules, ", "))
+		}
+	}
+
+	// source tags matched by a filter are verified when they are expanded
+	if len(p.TagFilter) > 0 {
+		return nil
+	}
+
+	_, err := semver.NewVersion(p.SourceTag)
+	if err != nil {
+		return fmt.Errorf("tag rules require a semantic version source tag, got %q: %w", p.SourceTag, err)
+	}
+
+	return nil
+}
+
+// validateTargetTags verifies the target tags are valid templates.
+func (p *DockerPromote) validateTargetTags() error {
+	for _, tag := range p.TargetTags {
+		_, err := parseTemplate("target_tags", tag)
+		if err != nil {
+			return fmt.Errorf("invalid target tag %s provided: %w", tag, err)
+		}
+	}
+
+	return nil
+}
+
+// validateTagFilter verifies the tag filter is properly configured.
+func (p *DockerPromote) validateTagFilter() error {
+	if len(p.TagFilter) == 0 {
+		return nil
+	}
+
+	// verify a single source tag is not also provided
+	if len(p.SourceTag) > 0 {
+		return fmt.Errorf("tag and tag_filter are mutually exclusive")
+	}
+
+	// verify the filtered tags have somewhere to be promoted to
+	if !p.KeepTagName && len(p.TargetTags) == 0 && len(p.TagRules) == 0 {
+		return fmt.Errorf("no target tags provided for tag filter (set keep_tag_name, target_tags or tag_rules)")
+	}
+
+	_, err := p.tagMatcher()
+
+	return err
+}
+
+// tagMatcher returns a function reporting whether a tag matches the tag filter.
+func (p *DockerPromote) tagMatcher() (func(string) bool, error) {
+	switch strings.ToLower(p.TagFilterType) {
+	case "", tagFilterGlob:
+		// verify the glob pattern is well formed
+		_, err := path.Match(p.TagFilter, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob tag filter %s provided: %w", p.TagFilter, err)
+		}
+
+		return func(tag string) bool {
+			ok, _ := path.Match(p.TagFilter, tag)
+
+			return ok
+		}, nil
+	case tagFilterRegex:
+		re, err := regexp.Compile(p.TagFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex tag filter %s provided: %w", p.TagFilter, err)
+		}
+
+		return re.MatchString, nil
+	case tagFilterSemver:
+		constraint, err := semver.NewConstraint(p.TagFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid semver tag filter %s provided: %w", p.TagFilter, err)
+		}
+
+		return func(tag string) bool {
+			version, err := semver.NewVersion(tag)
+			if err != nil {
+				return false
+			}
+
+			return constraint.Check(version)
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid tag filter type %s provided (valid types: %s)",
+			p.TagFilterType, strings.Join(tagFilterTypes, ", "))
+	}
+}
+
+// listTags captures the tags for the image in the source repository.
+func (p *DockerPromote) listTags(cli artifactory.ArtifactoryServicesManager) ([]string, error) {
+	source := p.SourceRepo
+	if len(source) == 0 {
+		source = p.TargetRepo
+	}
+
+	return listImageTags(cli, source, p.DockerRegistry)
+}
+
+// errImageNotFound defines the error type when
+// the image does not exist in the Docker repository.
+var errImageNotFound = errors.New("image not found")
+
+// listImageTags returns every tag for the image in the Docker repository.
+func listImageTags(cli artifactory.ArtifactoryServicesManager, repo, image string) ([]string, error) {
+	// send API call to list the tags for the image
+	resp, body, err := apiGet(cli, fmt.Sprintf("api/docker/%s/v2/%s/tags/list", repo, image))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("unable to list tags for %s in %s: %w", image, repo, errImageNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to list tags for %s in %s: %s", image, repo, resp.Status)
+	}
+
+	tags := new(dockerTags)
+
+	err = json.Unmarshal(body, tags)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse tags for %s in %s: %w", image, repo, err)
+	}
+
+	return tags.Tags, nil
+}
+
+// SourceTags returns the tags of the image to promote.
+//
+// When a tag filter is provided, the tags for the image are listed and
+// only the matching tags are returned. Semantic version tags are sorted
+// in ascending order so the highest version is promoted last.
+func (p *DockerPromote) SourceTags(cli artifactory.ArtifactoryServicesManager) ([]string, error) {
+	if len(p.TagFilter) == 0 {
+		return []string{p.SourceTag}, nil
+	}
+
+	match, err := p.tagMatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := p.listTags(cli)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+
+	for _, tag := range tags {
+		if match(tag) {
+			matched = append(matched, tag)
+		}
+	}
+
+	if strings.EqualFold(p.TagFilterType, tagFilterSemver) {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return semver.MustParse(matched[i]).LessThan(semver.MustParse(matched[j]))
+		})
+	}
+
+	logrus.Debugf("matched %d of %d tags for %s using %s", len(matched), len(tags), p.DockerRegistry, p.TagFilter)
+
+	return matched, nil
+}
+
+// ResolveTargetTags renders the target tag templates and expands the
+// semantic version source tag into tags according to the tag rules.
+func (p *DockerPromote) ResolveTargetTags(sourceTag string, build *BuildMetadata) ([]string, error) {
+	data := &tagTemplateData{
+		BuildMetadata: build,
+		SourceTag:     sourceTag,
+	}
+
+	var tags []string
+
+	// keep the name of the source tag in the target
+	if p.KeepTagName {
+		tags = append(tags, sourceTag)
+	}
+
+	version, err := semver.NewVersion(sourceTag)
+	if err == nil {
+		data.Major = version.Major()
+		data.Minor = version.Minor()
+		data.Patch = version.Patch()
+		data.Prerelease = version.Prerelease()
+	}
+
+	// render each target tag using the build and version information
+	for _, tag := range p.TargetTags {
+		rendered, err := renderTemplate("target_tags", tag, data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render target tag %s: %w", tag, err)
+		}
+
+		tags = append(tags, strings.TrimSpace(rendered))
+	}
+
+	// expand the source tag according to the tag rules
+	if len(p.TagRules) > 0 {
+		if version == nil {
+			return nil, fmt.Errorf("tag rules require a semantic version source tag, got %q: %w", sourceTag, err)
+		}
+
+		tags = append(tags, expandTagRules(version, p.TagRules)...)
+	}
+
+	var resolved []string
+
+	// remove empty and duplicate tags while preserving order
+	for _, tag := range tags {
+		if len(tag) == 0 || slices.Contains(resolved, tag) {
+			continue
+		}
+
+		if !dockerTagRe.MatchString(tag) {
+			return nil, fmt.Errorf("invalid target tag %q resolved", tag)
+		}
+
+		resolved = append(resolved, tag)
+	}
+
+	return resolved, nil
+}
+
+// expandTagRules generates the tags for the version according to the rules.
+//
+// Prerelease versions only generate the full version tag, so a release
+// candidate never replaces the major, minor or latest tags.
+func expandTagRules(version *semver.Version, rules []string) []string {
+	var tags []string
+
+	prerelease := len(version.Prerelease()) > 0
+
+	for _, rule := range rules {
+		switch strings.ToLower(rule) {
+		case tagRuleMajor:
+			if !prerelease {
+				tags = append(tags, fmt.Sprintf("%d", version.Major()))
+			}
+		case tagRuleMinor:
+			if !prerelease {
+				tags = append(tags, fmt.Sprintf("%d.%d", version.Major(), version.Minor()))
+			}
+		case tagRulePatch:
+			tag := fmt.Sprintf("%d.%d.%d", version.Major(), version.Minor(), version.Patch())
+
+			if prerelease {
+				tag = fmt.Sprintf("%s-%s", tag, version.Prerelease())
+			}
+
+			tags = append(tags, tag)
+		case tagRuleLatest:
+			if !prerelease {
+				tags = append(tags, tagRuleLatest)
+			}
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_DockerPromote_ResolveTargetTags(t *testing.T) {
+	// setup types
+	build := &BuildMetadata{
+		BuildNumber: "42",
+		Branch:      "main",
+		CommitShort: "7fd1a60b",
+	}
+
+	// setup tests
+	tests := []struct {
+		name      string
+		sourceTag string
+		tags      []string
+		rules     []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "static tags",
+			sourceTag: "latest",
+			tags:      []string{"foo", "bar"},
+			want:      []string{"foo", "bar"},
+		},
+		{
+			name:      "build templates",
+			sourceTag: "latest",
+			tags:      []string{"{{ .CommitShort }}", "b{{ .BuildNumber }}", "{{ .Branch }}-{{ .BuildNumber }}"},
+			want:      []string{"7fd1a60b", "b42", "main-42"},
+		},
+		{
+			name:      "version templates",
+			sourceTag: "v1.4.2",
+			tags:      []string{"{{ .Major }}.{{ .Minor }}-{{ .CommitShort }}"},
+			want:      []string{"1.4-7fd1a60b"},
+		},
+		{
+			name:      "all rules",
+			sourceTag: "v1.4.2",
+			rules:     []string{"major", "minor", "patch", "latest"},
+			want:      []string{"1", "1.4", "1.4.2", "latest"},
+		},
+		{
+			name:      "rules with tags removes duplicates",
+			sourceTag: "1.4.2",
+			tags:      []string{"1.4.2", "{{ .CommitShort }}"},
+			rules:     []string{"Patch", "minor"},
+			want:      []string{"1.4.2", "7fd1a60b", "1.4"},
+		},
+		{
+			name:      "prerelease skips major minor and latest",
+			sourceTag: "1.5.0-rc.1",
+			rules:     []string{"major", "minor", "patch", "latest"},
+			want:      []string{"1.5.0-rc.1"},
+		},
+		{
+			name:      "rules without semantic version",
+			sourceTag: "latest",
+			rules:     []string{"major"},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown template field",
+			sourceTag: "latest",
+			tags:      []string{"{{ .Foo }}"},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid docker tag",
+			sourceTag: "latest",
+			tags:      []string{"{{ .Branch }}/foo"},
+			wantErr:   true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &DockerPromote{
+				TargetTags: test.tags,
+				TagRules:   test.rules,
+			}
+
+			got, err := p.ResolveTargetTags(test.sourceTag, build)
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("ResolveTargetTags should have returned err")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("ResolveTargetTags returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ResolveTargetTags is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_newBuildMetadata(t *testing.T) {
+	// setup types
+	t.Setenv("VELA_BUILD_NUMBER", "42")
+	t.Setenv("VELA_BUILD_COMMIT", "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d")
+	t.Setenv("VELA_BUILD_CREATED", "1556720958")
+
+	b := newBuildMetadata()
+
+	if b.BuildNumber != "42" {
+		t.Errorf("BuildNumber is %v, want 42", b.BuildNumber)
+	}
+
+	if b.CommitShort != "7fd1a60b" {
+		t.Errorf("CommitShort is %v, want 7fd1a60b", b.CommitShort)
+	}
+
+	if b.Created.Unix() != 1556720958 {
+		t.Errorf("Created is %v, want 1556720958", b.Created.Unix())
+	}
+}
+
+func TestArtifactory_DockerPromote_SourceTags(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	c := &Config{
+		URL:      s.URL,
+		Username: mock.Username,
+		Password: mock.Password,
+		Client: &Client{
+			Retries:            3,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	cli, err := c.New()
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name       string
+		filter     string
+		filterType string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name: "no filter",
+			want: []string{""},
+		},
+		{
+			name:   "glob",
+			filter: "0.[12].*",
+			want:   []string{"0.1.0", "0.2.0"},
+		},
+		{
+			name:       "regex",
+			filter:     `^0\.[45]\.0$`,
+			filterType: "regex",
+			want:       []string{"0.4.0", "0.5.0"},
+		},
+		{
+			name:       "semver",
+			filter:     ">=0.2.0 <0.4",
+			filterType: "semver",
+			want:       []string{"0.2.0", "0.3.0"},
+		},
+		{
+			name:       "invalid type",
+			filter:     "*",
+			filterType: "foo",
+			wantErr:    true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &DockerPromote{
+				TargetRepo:     "docker",
+				DockerRegistry: "docker-dev",
+				TagFilter:      test.filter,
+				TagFilterType:  test.filterType,
+			}
+
+			got, err := p.SourceTags(*cli)
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("SourceTags should have returned err")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("SourceTags returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("SourceTags is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_TagFilter(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			TargetRepo:     "docker",
+			DockerRegistry: "docker-dev",
+			TagFilter:      ">=0.3.0",
+			TagFilterType:  "semver",
+			KeepTagName:    true,
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+}
+
+func TestArtifactory_DockerPromote_Exec_TagFilter_NotFound(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "docker-promote",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		DockerPromote: &DockerPromote{
+			TargetRepo:     "not-found",
+			DockerRegistry: "docker-dev",
+			TagFilter:      "*",
+			KeepTagName:    true,
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+const getPropAction = "get-prop"
+
+// envNameRe is a regular expression to match the characters
+// of a property name that are not valid in a variable name.
+var envNameRe = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+// GetProp represents the plugin configuration for reading the properties of an artifact.
+type GetProp struct {
+	// Path is the path to the artifact or folder to read properties from
+	Path string
+	// Keys are the names of the properties to export (exports every property if empty)
+	Keys []string
+	// Prefix is the prefix for the name of the variable exported for each property
+	Prefix string
+	// Output is the path to the dotenv file to write the properties to (uses VELA_OUTPUTS if empty)
+	Output string
+	// Required is a flag that enables failing when a property in the keys, or every property when no keys are provided, is missing (default: true)
+	Required bool
+}
+
+// Exec formats and runs the commands for reading the properties of an artifact in Artifactory.
+func (g *GetProp) Exec(cli artifactory.ArtifactoryServicesManager) error {
+	logrus.Trace("running get-prop with provided configuration")
+
+	start := time.Now()
+	logger := actionLogger(getPropAction, g.Path)
+
+	// resolve the single artifact or folder matching the path
+	path, err := g.resolve(cli)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Reading properties from %s", path)
+
+	// send API call to capture the properties of the artifact
+	item, err := cli.GetItemProps(path)
+	if err != nil {
+		return err
+	}
+
+	// the properties are not returned when the artifact has none
+	props := make(map[string][]string)
+	if item != nil {
+		props = item.Properties
+	}
+
+	keys := g.Keys
+	if len(keys) == 0 {
+		keys = sortedKeys(props)
+	}
+
+	// check if the artifact has no properties to export
+	if len(keys) == 0 {
+		if g.Required {
+			return fmt.Errorf("no properties found on %s", path)
+		}
+
+		logger.Warnf("No properties found on %s, no variables exported", path)
+	}
+
+	env := make(map[string]string)
+
+	var missing []string
+
+	for _, key := range keys {
+		values, ok := props[key]
+		if !ok {
+			missing = append(missing, key)
+
+			continue
+		}
+
+		name := g.envName(key)
+
+		logger.Infof("  %s -> %s", key, name)
+
+		env[name] = strings.Join(values, ",")
+	}
+
+	// check if any of the properties are missing
+	if len(missing) > 0 {
+		if g.Required {
+			return fmt.Errorf("missing required properties on %s: %s", path, strings.Join(missing, ", "))
+		}
+
+		logger.Warnf("Properties not found on %s, no variables exported for: %s", path, strings.Join(missing, ", "))
+	}
+
+	output := g.output()
+
+	err = writeDotenv(output, env)
+	if err != nil {
+		return err
+	}
+
+	withDuration(logger, start).WithField("success", len(env)).Infof("Exported %d variable(s) to %s", len(env), output)
+
+	return nil
+}
+
+// resolve returns the path to the single artifact or folder matching the path.
+func (g *GetProp) resolve(cli artifactory.ArtifactoryServicesManager) (string, error) {
+	path := strings.TrimSuffix(g.Path, "/")
+
+	// the path is used as-is when it does not contain wildcards
+	if !strings.ContainsAny(path, "*?") {
+		return path, nil
+	}
+
+	// create new search parameters
+	p := services.NewSearchParams()
+	p.CommonParams = &utils.CommonParams{Pattern: path, IncludeDirs: true}
+
+	// send API call to search for the artifact(s) matching the path
+	reader, err := cli.SearchFiles(p)
+	if err != nil {
+		return "", err
+	}
+
+	defer reader.Close()
+
+	var paths []string
+
+	for item := new(utils.ResultItem); reader.NextRecord(item) == nil; item = new(utils.ResultItem) {
+		paths = append(paths, strings.TrimSuffix(item.GetItemRelativePath(), "/"))
+	}
+
+	err = reader.GetError()
+	if err != nil {
+		return "", err
+	}
+
+	// verify a single artifact or folder matches the path
+	if len(paths) != 1 {
+		return "", fmt.Errorf("path %s must match a single artifact or folder, matched %d: %s", g.Path, len(paths), strings.Join(paths, ", "))
+	}
+
+	return paths[0], nil
+}
+
+// envName returns the name of the variable exported for the property.
+func (g *GetProp) envName(key string) string {
+	return g.Prefix + envNameRe.ReplaceAllString(strings.ToUpper(key), "_")
+}
+
+// output returns the path to the dotenv file to write the properties to.
+func (g *GetProp) output() string {
+	if len(g.Output) > 0 {
+		return g.Output
+	}
+
+	return os.Getenv("VELA_OUTPUTS")
+}
+
+// writeDotenv appends the variables to the dotenv file, so the
+// variables written by earlier steps to the file are preserved.
+func writeDotenv(path string, env map[string]string) error {
+	if len(env) == 0 {
+		return nil
+	}
+
+	data, err := godotenv.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("unable to marshal properties: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open output %s: %w", path, err)
+	}
+
+	defer f.Close()
+
+	_, err = f.WriteString(data + "\n")
+	if err != nil {
+		return fmt.Errorf("unable to write output %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate verifies the GetProp is properly configured.
+func (g *GetProp) Validate() error {
+	logrus.Trace("validating get-prop plugin configuration")
+
+	// verify path is provided
+	if len(g.Path) == 0 {
+		return fmt.Errorf("no get-prop path provided")
+	}
+
+	// verify an output is provided
+	if len(g.output()) == 0 {
+		return fmt.Errorf("no get-prop output provided and VELA_OUTPUTS is not set")
+	}
+
+	// verify keys do not contain separators
+	if slices.ContainsFunc(g.Keys, func(key string) bool { return len(key) == 0 || strings.ContainsAny(key, propNameChars) }) {
+		return fmt.Errorf("get-prop keys must not be empty or contain any of %s", propNameChars)
+	}
+
+	// verify the prefix is a valid variable name
+	if envNameRe.MatchString(g.Prefix) {
+		return fmt.Errorf("get-prop prefix %s must only contain A-Z, 0-9 and _", g.Prefix)
+	}
+
+	return nil
+}
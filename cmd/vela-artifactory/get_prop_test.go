@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_GetProp_Exec(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		getProp  *GetProp
+		existing string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:    "every property",
+			getProp: &GetProp{Path: "libs-release-local/existing/app.tar.gz", Prefix: "PROP_"},
+			want:    "PROP_BUILD_NUMBER=42\nPROP_OS=\"linux,darwin\"\nPROP_VCS_COMMIT=\"7fd1a60b01f91b314f59955a4e4d4e80d8edf11d\"\n",
+		},
+		{
+			name:     "keys",
+			getProp:  &GetProp{Path: "libs-release-local/existing/app.tar.gz", Keys: []string{"vcs.commit", "qa.status"}, Prefix: "APP_"},
+			existing: "VERSION=\"1.0.0\"\n",
+			want:     "VERSION=\"1.0.0\"\nAPP_VCS_COMMIT=\"7fd1a60b01f91b314f59955a4e4d4e80d8edf11d\"\n",
+		},
+		{
+			name:    "folder matching pattern",
+			getProp: &GetProp{Path: "docker/github/octocat/existing-*", Keys: []string{"build.number"}},
+			want:    "BUILD_NUMBER=42\n",
+		},
+		{
+			name:    "no properties",
+			getProp: &GetProp{Path: "libs-release-local/existing/noprops.txt"},
+		},
+		{
+			name:    "no properties required",
+			getProp: &GetProp{Path: "libs-release-local/existing/noprops.txt", Required: true},
+			wantErr: true,
+		},
+		{
+			name:    "required",
+			getProp: &GetProp{Path: "libs-release-local/existing/app.tar.gz", Keys: []string{"vcs.commit", "qa.status"}, Required: true},
+			wantErr: true,
+		},
+		{
+			name:    "multiple matches",
+			getProp: &GetProp{Path: "folders-local/app/*"},
+			wantErr: true,
+		},
+		{
+			name:    "not found",
+			getProp: &GetProp{Path: "libs-release-local/missing/app.tar.gz"},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, _ := requestServer(t)
+
+			output := filepath.Join(t.TempDir(), "outputs.env")
+
+			if len(test.existing) > 0 {
+				err := os.WriteFile(output, []byte(test.existing), 0o600)
+				if err != nil {
+					t.Fatalf("unable to write file: %v", err)
+				}
+			}
+
+			test.getProp.Output = output
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "get-prop",
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				GetProp: test.getProp,
+			}
+
+			err := p.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = p.Exec()
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Exec should have returned err")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			got, _ := os.ReadFile(output)
+
+			if string(got) != test.want {
+				t.Errorf("Exec wrote %q, want %q", string(got), test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_GetProp_Exec_NotRequired(t *testing.T) {
+	// setup types
+	s, _ := requestServer(t)
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "get-prop",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		GetProp: &GetProp{
+			Path:   "libs-release-local/existing/app.tar.gz",
+			Keys:   []string{"vcs.commit", "qa.status"},
+			Output: filepath.Join(t.TempDir(), "outputs.env"),
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	var logged bool
+
+	// the keys that were not found are logged when the properties are not required
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && strings.HasSuffix(entry.Message, ": qa.status") {
+			logged = true
+		}
+	}
+
+	if !logged {
+		t.Errorf("Exec did not log the missing property")
+	}
+}
+
+func TestArtifactory_GetProp_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		getProp *GetProp
+		outputs string
+		wantErr bool
+	}{
+		{
+			name:    "output",
+			getProp: &GetProp{Path: "foo/bar", Output: "props.env", Prefix: "PROP_"},
+		},
+		{
+			name:    "vela outputs",
+			getProp: &GetProp{Path: "foo/bar", Keys: []string{"vcs.commit"}, Required: true},
+			outputs: "/vela/outputs/.env",
+		},
+		{
+			name:    "no path",
+			getProp: &GetProp{Output: "props.env"},
+			wantErr: true,
+		},
+		{
+			name:    "no output",
+			getProp: &GetProp{Path: "foo/bar"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid key",
+			getProp: &GetProp{Path: "foo/bar", Output: "props.env", Keys: []string{"vcs.commit=abc"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid prefix",
+			getProp: &GetProp{Path: "foo/bar", Output: "props.env", Prefix: "prop-"},
+			wantErr: true,
+		},
+		{
+			name:    "required without keys",
+			getProp: &GetProp{Path: "foo/bar", Output: "props.env", Required: true},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("VELA_OUTPUTS", test.outputs)
+
+			err := test.getProp.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
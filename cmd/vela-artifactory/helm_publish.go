@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	json "github.com/ghodss/yaml"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const helmPublishAction = "helm-publish"
+
+// helmChartNameRe matches a valid Helm chart name.
+var helmChartNameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9._-]*[a-z0-9])?$`)
+
+// HelmPublish represents the plugin configuration for publishing Helm charts.
+type HelmPublish struct {
+	// Path is the target path in the Helm repository to publish the chart(s) to
+	Path string
+	// Sources are the paths or glob patterns for the chart archive(s) to publish
+	Sources []string
+	// Overwrite enables replacing a chart version that already exists in the repository
+	Overwrite bool
+	// DryRun is a flag to set to skip the index recalculation for the Helm repository
+	DryRun bool
+}
+
+// helmChart represents a chart archive to publish.
+type helmChart struct {
+	// File is the path to the chart archive
+	File string
+	// APIVersion is the chart API version from the Chart.yaml
+	APIVersion string `json:"apiVersion"`
+	// Name is the name of the chart from the Chart.yaml
+	Name string `json:"name"`
+	// Version is the version of the chart from the Chart.yaml
+	Version string `json:"version"`
+}
+
+// Exec formats and runs the commands for publishing Helm charts in Artifactory.
+func (h *HelmPublish) Exec(cli artifactory.ArtifactoryServicesManager) error {
+	logrus.Trace("running helm-publish with provided configuration")
+
+	start := time.Now()
+	logger := actionLogger(helmPublishAction, h.Path)
+
+	// read and verify every chart before publishing any chart
+	charts, err := h.Charts()
+	if err != nil {
+		return err
+	}
+
+	for _, chart := range charts {
+		target := h.target(chart)
+		entry := logger.WithField("artifact", target)
+
+		// verify the chart version is not already published
+		if !h.Overwrite {
+			exists, err := artifactExists(cli, target)
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				return fmt.Errorf("chart %s version %s already exists at %s", chart.Name, chart.Version, target)
+			}
+		}
+
+		// create new upload parameters
+		p := services.NewUploadParams()
+
+		// add chart configuration to upload parameters
+		p.CommonParams = &utils.CommonParams{
+			Pattern: chart.File,
+			Target:  target,
+		}
+
+		// upload to exact target path
+		p.Flat = true
+
+		// send API call to upload the chart in Artifactory
+		uploaded, failed, err := cli.UploadFiles(artifactory.UploadServiceOptions{FailFast: true}, p)
+		if err != nil {
+			return err
+		}
+
+		if failed > 0 || uploaded == 0 {
+			return fmt.Errorf("unable to upload chart %s to %s", chart.File, target)
+		}
+
+		entry.Infof("Published chart %s version %s from %s", chart.Name, chart.Version, chart.File)
+	}
+
+	repo := repoFromPath(h.Path)
+
+	// the index is recalculated through a raw API call the client dry run does not cover
+	if h.DryRun {
+		withDuration(logger, start).WithField("success", len(charts)).
+			Infof("Dry run enabled, skipping index recalculation for %s after %d chart(s)", repo, len(charts))
+
+		return nil
+	}
+
+	// send API call to recalculate the index for the Helm repository
+	resp, _, err := apiPost(cli, fmt.Sprintf("api/helm/%s/reindex", repo), nil)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unable to recalculate index for Helm repository %s: %s", repo, resp.Status)
+	}
+
+	withDuration(logger, start).WithField("success", len(charts)).
+		Infof("Published %d chart(s) and triggered index recalculation for %s", len(charts), repo)
+
+	return nil
+}
+
+// Charts returns the chart archives matching the sources, verifying
+// each archive contains a Chart.yaml with a valid name and version.
+func (h *HelmPublish) Charts() ([]*helmChart, error) {
+	var charts []*helmChart
+
+	for _, source := range h.Sources {
+		files, err := filepath.Glob(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid helm-publish source %s provided: %w", source, err)
+		}
+
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no chart archives found for helm-publish source %s", source)
+		}
+
+		for _, file := range files {
+			chart, err := readHelmChart(file)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chart archive %s: %w", file, err)
+			}
+
+			charts = append(charts, chart)
+		}
+	}
+
+	return charts, nil
+}
+
+// target returns the path in the repository to publish the chart to.
+func (h *HelmPublish) target(chart *helmChart) string {
+	return path.Join(h.Path, fmt.Sprintf("%s-%s.tgz", chart.Name, chart.Version))
+}
+
+// readHelmChart reads and verifies the Chart.yaml from the chart archive.
+func readHelmChart(file string) (*helmChart, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("chart archive is not gzip compressed: %w", err)
+	}
+
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no Chart.yaml found")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the Chart.yaml is stored in the top level directory of the chart
+		dir, name := path.Split(path.Clean(header.Name))
+		if name != "Chart.yaml" || strings.Count(dir, "/") != 1 {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		chart := &helmChart{File: file}
+
+		err = json.Unmarshal(data, chart)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse Chart.yaml: %w", err)
+		}
+
+		return chart, chart.Validate()
+	}
+}
+
+// Validate verifies the chart metadata is valid.
+func (c *helmChart) Validate() error {
+	// verify the chart API version is supported
+	if c.APIVersion != "v1" && c.APIVersion != "v2" {
+		return fmt.Errorf("invalid Chart.yaml apiVersion %q (valid versions: v1, v2)", c.APIVersion)
+	}
+
+	// verify the chart name is valid
+	if !helmChartNameRe.MatchString(c.Name) {
+		return fmt.Errorf("invalid Chart.yaml name %q", c.Name)
+	}
+
+	// verify the chart version is a semantic version
+	_, err := semver.NewVersion(c.Version)
+	if err != nil {
+		return fmt.Errorf("invalid Chart.yaml version %q: %w", c.Version, err)
+	}
+
+	return nil
+}
+
+// artifactExists reports whether an artifact is stored at the path.
+func artifactExists(cli artifactory.ArtifactoryServicesManager, path string) (bool, error) {
+	// send API call to capture the artifact information
+	resp, _, err := apiGet(cli, fmt.Sprintf("api/storage/%s", strings.TrimPrefix(path, "/")))
+	if err != nil {
+		return false, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unable to check for %s: %s", path, resp.Status)
+	}
+}
+
+// Validate verifies the HelmPublish is properly configured.
+func (h *HelmPublish) Validate() error {
+	logrus.Trace("validating helm-publish plugin configuration")
+
+	// verify path is provided
+	if len(h.Path) == 0 {
+		return fmt.Errorf("no helm-publish path provided")
+	}
+
+	// verify sources are provided
+	if len(h.Sources) == 0 {
+		return fmt.Errorf("no helm-publish sources provided")
+	}
+
+	// verify the chart archives are valid
+	_, err := h.Charts()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
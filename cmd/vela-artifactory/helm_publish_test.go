@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+// writeChart writes a chart archive with the Chart.yaml to the directory and returns its path.
+func writeChart(t *testing.T, dir, file, chartYAML string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, file)
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("unable to create chart archive: %v", err)
+	}
+
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	files := map[string]string{
+		"mychart/Chart.yaml":            chartYAML,
+		"mychart/values.yaml":           "replicaCount: 1\n",
+		"mychart/charts/dep/Chart.yaml": "apiVersion: v2\nname: dep\nversion: 0.0.1\n",
+	}
+
+	for name, content := range files {
+		err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))})
+		if err != nil {
+			t.Fatalf("unable to write chart archive: %v", err)
+		}
+
+		_, err = tw.Write([]byte(content))
+		if err != nil {
+			t.Fatalf("unable to write chart archive: %v", err)
+		}
+	}
+
+	tw.Close()
+	gz.Close()
+
+	return path
+}
+
+func TestArtifactory_HelmPublish_Exec(t *testing.T) {
+	// setup types
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut || r.Method == http.MethodPost {
+			mu.Lock()
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	dir := t.TempDir()
+	writeChart(t, dir, "mychart-1.0.0.tgz", "apiVersion: v2\nname: mychart\nversion: 1.0.0\n")
+	writeChart(t, dir, "other.tgz", "apiVersion: v2\nname: mychart\nversion: 1.1.0-rc.1\n")
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "helm-publish",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		HelmPublish: &HelmPublish{
+			Path:    "helm/charts",
+			Sources: []string{filepath.Join(dir, "*.tgz")},
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := []string{
+		"PUT /helm/charts/mychart-1.0.0.tgz",
+		"PUT /helm/charts/mychart-1.1.0-rc.1.tgz",
+		"POST /api/helm/helm/reindex",
+	}
+
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("Exec requests are %v, want %v", requests, want)
+	}
+}
+
+func TestArtifactory_HelmPublish_Exec_DryRun(t *testing.T) {
+	// setup types
+	s, requests := requestServer(t)
+
+	dir := t.TempDir()
+	writeChart(t, dir, "mychart-1.0.0.tgz", "apiVersion: v2\nname: mychart\nversion: 1.0.0\n")
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "helm-publish",
+			DryRun:   true,
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		HelmPublish: &HelmPublish{
+			Path:    "helm/charts",
+			Sources: []string{filepath.Join(dir, "*.tgz")},
+			DryRun:  true,
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	for _, request := range requests() {
+		if strings.HasPrefix(request, http.MethodPut+" ") || strings.HasPrefix(request, http.MethodPost+" ") {
+			t.Errorf("Exec sent %s in dry run", request)
+		}
+	}
+}
+
+func TestArtifactory_HelmPublish_Exec_Exists(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	dir := t.TempDir()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "helm-publish",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		HelmPublish: &HelmPublish{
+			Path:    "helm/existing",
+			Sources: []string{writeChart(t, dir, "mychart.tgz", "apiVersion: v2\nname: mychart\nversion: 1.0.0\n")},
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+
+	p.HelmPublish.Overwrite = true
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+}
+
+func TestArtifactory_HelmPublish_Validate(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	valid := writeChart(t, dir, "valid.tgz", "apiVersion: v2\nname: mychart\nversion: 1.0.0\n")
+
+	err := os.WriteFile(filepath.Join(dir, "plain.tgz"), []byte("not a chart"), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		h       *HelmPublish
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			h:    &HelmPublish{Path: "helm", Sources: []string{valid}},
+		},
+		{
+			name:    "no path",
+			h:       &HelmPublish{Sources: []string{valid}},
+			wantErr: true,
+		},
+		{
+			name:    "no sources",
+			h:       &HelmPublish{Path: "helm"},
+			wantErr: true,
+		},
+		{
+			name:    "no matching sources",
+			h:       &HelmPublish{Path: "helm", Sources: []string{filepath.Join(dir, "*.zip")}},
+			wantErr: true,
+		},
+		{
+			name:    "not gzip compressed",
+			h:       &HelmPublish{Path: "helm", Sources: []string{filepath.Join(dir, "plain.tgz")}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			h:       &HelmPublish{Path: "helm", Sources: []string{writeChart(t, dir, "name.tgz", "apiVersion: v2\nname: ../chart\nversion: 1.0.0\n")}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			h:       &HelmPublish{Path: "helm", Sources: []string{writeChart(t, dir, "version.tgz", "apiVersion: v2\nname: mychart\nversion: latest\n")}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid api version",
+			h:       &HelmPublish{Path: "helm", Sources: []string{writeChart(t, dir, "api.tgz", "name: mychart\nversion: 1.0.0\n")}},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.h.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/sirupsen/logrus"
+)
+
+// redacted is the value used in place of secrets in log output.
+const redacted = "[REDACTED]"
+
+const (
+	// logFormatText configures the plugin to emit logs as text.
+	logFormatText = "text"
+	// logFormatJSON configures the plugin to emit logs as JSON.
+	logFormatJSON = "json"
+)
+
+// logFormatter returns the logrus formatter for the log format.
+func logFormatter(format string) (logrus.Formatter, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case logFormatJSON:
+		return &logrus.JSONFormatter{}, nil
+	case logFormatText, "":
+		return &logrus.TextFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("invalid log format provided: %s (Valid log formats: %s, %s)", format, logFormatText, logFormatJSON)
+	}
+}
+
+// actionLogger creates a logrus entry with the structured
+// fields shared by every action for the artifact(s) path.
+func actionLogger(action, path string) *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{
+		"action": action,
+		"repo":   repoFromPath(path),
+		"path":   path,
+	})
+}
+
+// withDuration adds the time elapsed since start to the logrus entry.
+func withDuration(entry *logrus.Entry, start time.Time) *logrus.Entry {
+	return entry.WithField("duration", time.Since(start).Round(time.Millisecond).String())
+}
+
+var (
+	// A regular expression to match credentials embedded in the user info of a URL.
+	userInfoRe = regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`)
+
+	// A regular expression to match secrets provided as query parameters in a URL.
+	secretParamRe = regexp.MustCompile(`(?i)([?&;](?:access_token|token|password|passwd|apikey|api_key)=)[^&;\s"]+`)
+
+	// A regular expression to match secrets provided in HTTP headers.
+	secretHeaderRe = regexp.MustCompile(`(?i)((?:authorization|x-jfrog-art-api)"?\s*[:=]\s*\[?"?(?:bearer\s+|basic\s+)?)[^\s",\]]+`)
+)
+
+// redactor removes secrets from log output.
+type redactor struct {
+	mu      sync.RWMutex
+	secrets []string
+}
+
+// newRedactor creates a redactor for the provided secrets.
+func newRedactor(secrets ...string) *redactor {
+	r := new(redactor)
+
+	for _, secret := range secrets {
+		r.Add(secret)
+	}
+
+	return r
+}
+
+// Add registers a secret to remove from log output.
+func (r *redactor) Add(secret string) {
+	// ignore empty secrets to avoid redacting every message
+	if r == nil || len(secret) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.secrets = append(r.secrets, secret)
+}
+
+// Redact returns the message with all known secrets, credentials
+// embedded in URLs and secret HTTP headers removed.
+func (r *redactor) Redact(msg string) string {
+	if r != nil {
+		r.mu.RLock()
+
+		for _, secret := range r.secrets {
+			msg = strings.ReplaceAll(msg, secret, redacted)
+		}
+
+		r.mu.RUnlock()
+	}
+
+	msg = userInfoRe.ReplaceAllString(msg, "${1}"+redacted+"@")
+	msg = secretParamRe.ReplaceAllString(msg, "${1}"+redacted)
+	msg = secretHeaderRe.ReplaceAllString(msg, "${1}"+redacted)
+
+	return msg
+}
+
+// jfrogLogLevel maps the plugin log level to the Artifactory client log level.
+func jfrogLogLevel(level logrus.Level) log.LevelType {
+	switch level {
+	case logrus.TraceLevel:
+		return log.VERBOSE
+	case logrus.DebugLevel:
+		return log.DEBUG
+	case logrus.InfoLevel:
+		return log.INFO
+	case logrus.WarnLevel:
+		return log.WARN
+	default:
+		return log.ERROR
+	}
+}
+
+// jfrogLogger routes the Artifactory client logs through logrus.
+//
+// https://pkg.go.dev/github.com/jfrog/jfrog-client-go/utils/log#Log
+type jfrogLogger struct {
+	redactor *redactor
+}
+
+// newJFrogLogger creates a logger for the Artifactory client.
+func newJFrogLogger(r *redactor) *jfrogLogger {
+	return &jfrogLogger{redactor: r}
+}
+
+// format joins the arguments into a redacted message.
+func (l *jfrogLogger) format(a ...interface{}) string {
+	return l.redactor.Redact(strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
+}
+
+// Verbose logs the arguments at the trace level.
+func (l *jfrogLogger) Verbose(a ...interface{}) {
+	if logrus.IsLevelEnabled(logrus.TraceLevel) {
+		logrus.WithField("source", "jfrog").Trace(l.format(a...))
+	}
+}
+
+// Debug logs the arguments at the debug level.
+func (l *jfrogLogger) Debug(a ...interface{}) {
+	if logrus.IsLevelEnabled(logrus.DebugLevel) {
+		logrus.WithField("source", "jfrog").Debug(l.format(a...))
+	}
+}
+
+// Info logs the arguments at the info level.
+func (l *jfrogLogger) Info(a ...interface{}) {
+	if logrus.IsLevelEnabled(logrus.InfoLevel) {
+		logrus.WithField("source", "jfrog").Info(l.format(a...))
+	}
+}
+
+// Warn logs the arguments at the warn level.
+func (l *jfrogLogger) Warn(a ...interface{}) {
+	if logrus.IsLevelEnabled(logrus.WarnLevel) {
+		logrus.WithField("source", "jfrog").Warn(l.format(a...))
+	}
+}
+
+// Error logs the arguments at the error level.
+func (l *jfrogLogger) Error(a ...interface{}) {
+	if logrus.IsLevelEnabled(logrus.ErrorLevel) {
+		logrus.WithField("source", "jfrog").Error(l.format(a...))
+	}
+}
+
+// Output logs the arguments at the info level.
+func (l *jfrogLogger) Output(a ...interface{}) {
+	if logrus.IsLevelEnabled(logrus.InfoLevel) {
+		logrus.WithField("source", "jfrog").Info(l.format(a...))
+	}
+}
+
+// GetLogLevel returns the Artifactory client log level mapped from the plugin log level.
+func (l *jfrogLogger) GetLogLevel() log.LevelType {
+	return jfrogLogLevel(logrus.GetLevel())
+}
+
+// retryLogger routes the retryable HTTP client logs through logrus.
+//
+// https://pkg.go.dev/github.com/hashicorp/go-retryablehttp#LeveledLogger
+type retryLogger struct {
+	redactor *redactor
+}
+
+// newRetryLogger creates a logger for the retryable HTTP client.
+func newRetryLogger(r *redactor) *retryLogger {
+	return &retryLogger{redactor: r}
+}
+
+// entry creates a redacted logrus entry from the key value pairs.
+func (l *retryLogger) entry(keysAndValues ...interface{}) *logrus.Entry {
+	fields := logrus.Fields{"source": "http"}
+
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		value := keysAndValues[i+1]
+
+		// only textual values may contain secrets
+		switch v := value.(type) {
+		case string:
+			value = l.redactor.Redact(v)
+		case fmt.Stringer:
+			value = l.redactor.Redact(v.String())
+		case error:
+			value = l.redactor.Redact(v.Error())
+		}
+
+		fields[fmt.Sprint(keysAndValues[i])] = value
+	}
+
+	return logrus.WithFields(fields)
+}
+
+// Error logs the message at the error level.
+func (l *retryLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.entry(keysAndValues...).Error(l.redactor.Redact(msg))
+}
+
+// Info logs the message at the info level.
+func (l *retryLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.entry(keysAndValues...).Info(l.redactor.Redact(msg))
+}
+
+// Debug logs the message at the debug level.
+func (l *retryLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.entry(keysAndValues...).Debug(l.redactor.Redact(msg))
+}
+
+// requestLogHook logs every attempt made by the retryable HTTP client.
+func (l *retryLogger) requestLogHook(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	l.entry(
+		"method", req.Method,
+		"url", req.URL.String(),
+		// retryable HTTP client attempts are zero based
+		"attempt", attempt+1,
+	).Debug("sending request to Artifactory")
+}
+
+// Warn logs the message at the warn level.
+func (l *retryLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.entry(keysAndValues...).Warn(l.redactor.Redact(msg))
+}
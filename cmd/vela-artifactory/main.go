@@ -66,6 +66,17 @@ func main() {
 					cli.File("/vela/secrets/artifactory/log_level"),
 				),
 			},
+			&cli.StringFlag{
+				Name:  "log.format",
+				Value: "text",
+				Usage: "set log format - options: (text|json)",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_LOG_FORMAT"),
+					cli.EnvVar("ARTIFACTORY_LOG_FORMAT"),
+					cli.File("/vela/parameters/artifactory/log_format"),
+					cli.File("/vela/secrets/artifactory/log_format"),
+				),
+			},
 			&cli.StringFlag{
 				Name:  "path",
 				Usage: "source/target path to artifact(s) for action",
@@ -86,6 +97,76 @@ func main() {
 					cli.File("/vela/secrets/artifactory/recursive"),
 				),
 			},
+			&cli.StringSliceFlag{
+				Name:  "exclusions",
+				Usage: "path patterns for artifact(s) to leave out of the source/target path or upload sources",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_EXCLUSIONS"),
+					cli.EnvVar("ARTIFACTORY_EXCLUSIONS"),
+					cli.File("/vela/parameters/artifactory/exclusions"),
+					cli.File("/vela/secrets/artifactory/exclusions"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "include_props",
+				Usage: "properties the artifact(s) in the source/target path must have",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_INCLUDE_PROPS"),
+					cli.EnvVar("ARTIFACTORY_INCLUDE_PROPS"),
+					cli.File("/vela/parameters/artifactory/include_props"),
+					cli.File("/vela/secrets/artifactory/include_props"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "exclude_props",
+				Usage: "properties the artifact(s) in the source/target path must not have",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_EXCLUDE_PROPS"),
+					cli.EnvVar("ARTIFACTORY_EXCLUDE_PROPS"),
+					cli.File("/vela/parameters/artifactory/exclude_props"),
+					cli.File("/vela/secrets/artifactory/exclude_props"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "aql",
+				Usage: "AQL items.find query for selecting artifact(s) instead of the source/target path",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_AQL"),
+					cli.EnvVar("ARTIFACTORY_AQL"),
+					cli.File("/vela/parameters/artifactory/aql"),
+					cli.File("/vela/secrets/artifactory/aql"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "aql_file",
+				Usage: "path to a file containing the AQL items.find query for selecting artifact(s)",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_AQL_FILE"),
+					cli.EnvVar("ARTIFACTORY_AQL_FILE"),
+					cli.File("/vela/parameters/artifactory/aql_file"),
+					cli.File("/vela/secrets/artifactory/aql_file"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "required_props",
+				Usage: "properties the artifact(s) must have for the action to proceed",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_REQUIRED_PROPS"),
+					cli.EnvVar("ARTIFACTORY_REQUIRED_PROPS"),
+					cli.File("/vela/parameters/artifactory/required_props"),
+					cli.File("/vela/secrets/artifactory/required_props"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "forbidden_props",
+				Usage: "properties the artifact(s) must not have for the action to proceed",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_FORBIDDEN_PROPS"),
+					cli.EnvVar("ARTIFACTORY_FORBIDDEN_PROPS"),
+					cli.File("/vela/parameters/artifactory/forbidden_props"),
+					cli.File("/vela/secrets/artifactory/forbidden_props"),
+				),
+			},
 
 			// Config Flags
 
@@ -119,6 +200,47 @@ func main() {
 					cli.File("/vela/secrets/artifactory/api_key"),
 				),
 			},
+			&cli.StringFlag{
+				Name:  "config.auth_method",
+				Usage: "method for authenticating with the Artifactory instance - options: (basic|api_key|access_token|oidc|client_cert)",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_AUTH_METHOD"),
+					cli.EnvVar("ARTIFACTORY_AUTH_METHOD"),
+					cli.File("/vela/parameters/artifactory/auth_method"),
+					cli.File("/vela/secrets/artifactory/auth_method"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "config.oidc_token",
+				Usage: "OIDC ID token to exchange for an access token with the Artifactory instance",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_OIDC_TOKEN"),
+					cli.EnvVar("ARTIFACTORY_OIDC_TOKEN"),
+					cli.EnvVar("VELA_ID_TOKEN"),
+					cli.File("/vela/parameters/artifactory/oidc_token"),
+					cli.File("/vela/secrets/artifactory/oidc_token"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "config.oidc_provider",
+				Usage: "name of the OIDC provider configured in the Artifactory instance",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_OIDC_PROVIDER"),
+					cli.EnvVar("ARTIFACTORY_OIDC_PROVIDER"),
+					cli.File("/vela/parameters/artifactory/oidc_provider"),
+					cli.File("/vela/secrets/artifactory/oidc_provider"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "config.preflight",
+				Usage: "enables verifying connectivity, credentials and permissions before performing the action",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PREFLIGHT"),
+					cli.EnvVar("ARTIFACTORY_PREFLIGHT"),
+					cli.File("/vela/parameters/artifactory/preflight"),
+					cli.File("/vela/secrets/artifactory/preflight"),
+				),
+			},
 			&cli.StringFlag{
 				Name:  "config.token",
 				Usage: "Access/Identity token for communication with the Artifactory instance",
@@ -215,6 +337,17 @@ func main() {
 				),
 			},
 
+			&cli.StringFlag{
+				Name:  "client.trace_file",
+				Usage: "file path to record every request and response sent to the Artifactory instance (HAR format)",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_HTTP_CLIENT_TRACE_FILE"),
+					cli.EnvVar("ARTIFACTORY_HTTP_CLIENT_TRACE_FILE"),
+					cli.File("/vela/parameters/artifactory/http_client_trace_file"),
+					cli.File("/vela/secrets/artifactory/http_client_trace_file"),
+				),
+			},
+
 			// Copy Flags
 
 			&cli.BoolFlag{
@@ -238,6 +371,50 @@ func main() {
 				),
 			},
 
+			// Docker Cleanup Flags
+
+			&cli.StringFlag{
+				Name:  "docker_cleanup.repo",
+				Usage: "name of the docker repository containing the image",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_REPO"),
+					cli.EnvVar("ARTIFACTORY_REPO"),
+					cli.File("/vela/parameters/artifactory/repo"),
+					cli.File("/vela/secrets/artifactory/repo"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "docker_cleanup.image",
+				Usage: "path to image in docker registry",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_IMAGE"),
+					cli.EnvVar("ARTIFACTORY_IMAGE"),
+					cli.File("/vela/parameters/artifactory/image"),
+					cli.File("/vela/secrets/artifactory/image"),
+				),
+			},
+			&cli.IntFlag{
+				Name:  "docker_cleanup.keep",
+				Value: defaultKeep,
+				Usage: "number of the newest tags to keep for the image",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_KEEP"),
+					cli.EnvVar("ARTIFACTORY_KEEP"),
+					cli.File("/vela/parameters/artifactory/keep"),
+					cli.File("/vela/secrets/artifactory/keep"),
+				),
+			},
+			&cli.StringSliceFlag{
+				Name:  "docker_cleanup.protect",
+				Usage: "glob patterns for tags that are never removed",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PROTECT"),
+					cli.EnvVar("ARTIFACTORY_PROTECT"),
+					cli.File("/vela/parameters/artifactory/protect"),
+					cli.File("/vela/secrets/artifactory/protect"),
+				),
+			},
+
 			// Docker Promote Flags
 
 			&cli.StringFlag{
@@ -300,6 +477,78 @@ func main() {
 					cli.File("/vela/secrets/artifactory/target_tags"),
 				),
 			},
+			&cli.StringSliceFlag{
+				Name:  "docker_promote.tag_rules",
+				Usage: "rules for expanding a semantic version tag into target tags (major, minor, patch, latest)",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_TAG_RULES"),
+					cli.EnvVar("ARTIFACTORY_TAG_RULES"),
+					cli.File("/vela/parameters/artifactory/tag_rules"),
+					cli.File("/vela/secrets/artifactory/tag_rules"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "docker_promote.tag_filter",
+				Usage: "pattern for matching the tags of the image to promote",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_TAG_FILTER"),
+					cli.EnvVar("ARTIFACTORY_TAG_FILTER"),
+					cli.File("/vela/parameters/artifactory/tag_filter"),
+					cli.File("/vela/secrets/artifactory/tag_filter"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "docker_promote.tag_filter_type",
+				Value: tagFilterGlob,
+				Usage: "type of pattern for the tag filter (glob, regex or semver)",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_TAG_FILTER_TYPE"),
+					cli.EnvVar("ARTIFACTORY_TAG_FILTER_TYPE"),
+					cli.File("/vela/parameters/artifactory/tag_filter_type"),
+					cli.File("/vela/secrets/artifactory/tag_filter_type"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "docker_promote.keep_tag_name",
+				Usage: "set to keep the name of the source tag in the target",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_KEEP_TAG_NAME"),
+					cli.EnvVar("ARTIFACTORY_KEEP_TAG_NAME"),
+					cli.File("/vela/parameters/artifactory/keep_tag_name"),
+					cli.File("/vela/secrets/artifactory/keep_tag_name"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "docker_promote.images",
+				Usage: "images to promote in a single step",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_IMAGES"),
+					cli.EnvVar("ARTIFACTORY_IMAGES"),
+					cli.File("/vela/parameters/artifactory/images"),
+					cli.File("/vela/secrets/artifactory/images"),
+				),
+			},
+			&cli.IntFlag{
+				Name:  "docker_promote.concurrency",
+				Value: defaultConcurrency,
+				Usage: "maximum number of images to promote at the same time",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_CONCURRENCY"),
+					cli.EnvVar("ARTIFACTORY_CONCURRENCY"),
+					cli.File("/vela/parameters/artifactory/concurrency"),
+					cli.File("/vela/secrets/artifactory/concurrency"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "docker_promote.rollback",
+				Usage: "set to remove promoted images when promoting an image fails",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_ROLLBACK"),
+					cli.EnvVar("ARTIFACTORY_ROLLBACK"),
+					cli.File("/vela/parameters/artifactory/rollback"),
+					cli.File("/vela/secrets/artifactory/rollback"),
+				),
+			},
 			&cli.BoolFlag{
 				Name:  "docker_promote.copy",
 				Value: true,
@@ -311,6 +560,16 @@ func main() {
 					cli.File("/vela/secrets/artifactory/copy"),
 				),
 			},
+			&cli.StringFlag{
+				Name:  "docker_promote.properties",
+				Usage: "properties to set on the image after promotion",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PROPS"),
+					cli.EnvVar("ARTIFACTORY_PROPS"),
+					cli.File("/vela/parameters/artifactory/props"),
+					cli.File("/vela/secrets/artifactory/props"),
+				),
+			},
 			&cli.BoolFlag{
 				Name:  "docker_promote.props",
 				Usage: "property to be set on the artifact when it is being promoted",
@@ -322,6 +581,254 @@ func main() {
 				),
 			},
 
+			// Get Prop Flags
+
+			&cli.StringSliceFlag{
+				Name:  "get_prop.keys",
+				Usage: "names of the properties to export (exports every property if empty)",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_KEYS"),
+					cli.EnvVar("ARTIFACTORY_KEYS"),
+					cli.File("/vela/parameters/artifactory/keys"),
+					cli.File("/vela/secrets/artifactory/keys"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "get_prop.prefix",
+				Usage: "prefix for the name of the variable exported for each property",
+				Value: "PROP_",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PREFIX"),
+					cli.EnvVar("ARTIFACTORY_PREFIX"),
+					cli.File("/vela/parameters/artifactory/prefix"),
+					cli.File("/vela/secrets/artifactory/prefix"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "get_prop.output",
+				Usage: "path to the dotenv file to write the properties to (uses VELA_OUTPUTS if empty)",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_OUTPUT"),
+					cli.EnvVar("ARTIFACTORY_OUTPUT"),
+					cli.File("/vela/parameters/artifactory/output"),
+					cli.File("/vela/secrets/artifactory/output"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "get_prop.required",
+				Usage: "enables failing when a property in the keys, or every property when no keys are provided, is missing",
+				Value: true,
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_REQUIRED"),
+					cli.EnvVar("ARTIFACTORY_REQUIRED"),
+					cli.File("/vela/parameters/artifactory/required"),
+					cli.File("/vela/secrets/artifactory/required"),
+				),
+			},
+
+			// Helm Publish Flags
+
+			&cli.StringSliceFlag{
+				Name:  "helm_publish.sources",
+				Usage: "list of chart archive(s) to publish",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_SOURCES"),
+					cli.EnvVar("ARTIFACTORY_SOURCES"),
+					cli.File("/vela/parameters/artifactory/sources"),
+					cli.File("/vela/secrets/artifactory/sources"),
+				),
+			},
+			&cli.BoolFlag{
+				Name:  "helm_publish.overwrite",
+				Value: true,
+				Usage: "set to replace a chart version that already exists",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_OVERWRITE"),
+					cli.EnvVar("ARTIFACTORY_OVERWRITE"),
+					cli.File("/vela/parameters/artifactory/overwrite"),
+					cli.File("/vela/secrets/artifactory/overwrite"),
+				),
+			},
+
+			// Maven Deploy Flags
+
+			&cli.StringFlag{
+				Name:  "maven_deploy.repo",
+				Usage: "name of the maven repository to deploy to",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_REPO"),
+					cli.EnvVar("ARTIFACTORY_REPO"),
+					cli.File("/vela/parameters/artifactory/repo"),
+					cli.File("/vela/secrets/artifactory/repo"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "maven_deploy.pom",
+				Usage: "path to the POM for the artifact",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_POM"),
+					cli.EnvVar("ARTIFACTORY_POM"),
+					cli.File("/vela/parameters/artifactory/pom"),
+					cli.File("/vela/secrets/artifactory/pom"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "maven_deploy.group_id",
+				Usage: "group ID of the artifact",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_GROUP_ID"),
+					cli.EnvVar("ARTIFACTORY_GROUP_ID"),
+					cli.File("/vela/parameters/artifactory/group_id"),
+					cli.File("/vela/secrets/artifactory/group_id"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "maven_deploy.artifact_id",
+				Usage: "artifact ID of the artifact",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_ARTIFACT_ID"),
+					cli.EnvVar("ARTIFACTORY_ARTIFACT_ID"),
+					cli.File("/vela/parameters/artifactory/artifact_id"),
+					cli.File("/vela/secrets/artifactory/artifact_id"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "maven_deploy.version",
+				Usage: "version of the artifact",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_VERSION"),
+					cli.EnvVar("ARTIFACTORY_VERSION"),
+					cli.File("/vela/parameters/artifactory/version"),
+					cli.File("/vela/secrets/artifactory/version"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "maven_deploy.packaging",
+				Usage: "packaging of the artifact",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PACKAGING"),
+					cli.EnvVar("ARTIFACTORY_PACKAGING"),
+					cli.File("/vela/parameters/artifactory/packaging"),
+					cli.File("/vela/secrets/artifactory/packaging"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "maven_deploy.artifact",
+				Usage: "path to the main artifact file",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_ARTIFACT"),
+					cli.EnvVar("ARTIFACTORY_ARTIFACT"),
+					cli.File("/vela/parameters/artifactory/artifact"),
+					cli.File("/vela/secrets/artifactory/artifact"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "maven_deploy.sources_jar",
+				Usage: "path to the sources jar for the artifact",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_SOURCES_JAR"),
+					cli.EnvVar("ARTIFACTORY_SOURCES_JAR"),
+					cli.File("/vela/parameters/artifactory/sources_jar"),
+					cli.File("/vela/secrets/artifactory/sources_jar"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "maven_deploy.javadoc_jar",
+				Usage: "path to the javadoc jar for the artifact",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_JAVADOC_JAR"),
+					cli.EnvVar("ARTIFACTORY_JAVADOC_JAR"),
+					cli.File("/vela/parameters/artifactory/javadoc_jar"),
+					cli.File("/vela/secrets/artifactory/javadoc_jar"),
+				),
+			},
+
+			// Move Flags
+
+			&cli.BoolFlag{
+				Name:  "move.flat",
+				Usage: "enables removing source directory hierarchy",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_FLAT"),
+					cli.EnvVar("ARTIFACTORY_FLAT"),
+					cli.File("/vela/parameters/artifactory/flat"),
+					cli.File("/vela/secrets/artifactory/flat"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "move.target",
+				Usage: "target path to move artifact(s) to",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_TARGET"),
+					cli.EnvVar("ARTIFACTORY_TARGET"),
+					cli.File("/vela/parameters/artifactory/target"),
+					cli.File("/vela/secrets/artifactory/target"),
+				),
+			},
+
+			// OCI Push Flags
+
+			&cli.StringFlag{
+				Name:  "oci_push.source",
+				Usage: "path to an OCI image layout directory or tarball, or a docker save tarball",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_SOURCE"),
+					cli.EnvVar("ARTIFACTORY_SOURCE"),
+					cli.File("/vela/parameters/artifactory/source"),
+					cli.File("/vela/secrets/artifactory/source"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "oci_push.ref_name",
+				Usage: "name of the reference to push from an OCI image layout with multiple manifests",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_REF_NAME"),
+					cli.EnvVar("ARTIFACTORY_REF_NAME"),
+					cli.File("/vela/parameters/artifactory/ref_name"),
+					cli.File("/vela/secrets/artifactory/ref_name"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "oci_push.target_repo",
+				Usage: "name of the docker repository to push the image to",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_TARGET_REPO"),
+					cli.EnvVar("ARTIFACTORY_TARGET_REPO"),
+					cli.File("/vela/parameters/artifactory/target_repo"),
+					cli.File("/vela/secrets/artifactory/target_repo"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "oci_push.image",
+				Usage: "path to image in docker registry",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_IMAGE"),
+					cli.EnvVar("ARTIFACTORY_IMAGE"),
+					cli.File("/vela/parameters/artifactory/image"),
+					cli.File("/vela/secrets/artifactory/image"),
+				),
+			},
+			&cli.StringSliceFlag{
+				Name:  "oci_push.tags",
+				Usage: "tags to assign the image after pushing",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_TAGS"),
+					cli.EnvVar("ARTIFACTORY_TAGS"),
+					cli.File("/vela/parameters/artifactory/tags"),
+					cli.File("/vela/secrets/artifactory/tags"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "oci_push.props",
+				Usage: "properties to set on the image after pushing",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_PROPS"),
+					cli.EnvVar("ARTIFACTORY_PROPS"),
+					cli.File("/vela/parameters/artifactory/props"),
+					cli.File("/vela/secrets/artifactory/props"),
+				),
+			},
+
 			// Set Prop Flags
 
 			&cli.StringFlag{
@@ -334,6 +841,17 @@ func main() {
 					cli.File("/vela/secrets/artifactory/props"),
 				),
 			},
+			&cli.StringFlag{
+				Name:  "set_prop.scope",
+				Usage: "type of item(s) matching the path to set properties on (contents, folders or both)",
+				Value: setPropScopeContents,
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_SCOPE"),
+					cli.EnvVar("ARTIFACTORY_SCOPE"),
+					cli.File("/vela/parameters/artifactory/scope"),
+					cli.File("/vela/secrets/artifactory/scope"),
+				),
+			},
 
 			// Upload Flags
 
@@ -369,7 +887,7 @@ func main() {
 			},
 			&cli.StringSliceFlag{
 				Name:  "upload.sources",
-				Usage: "list of artifact(s) to upload",
+				Usage: "list of artifact(s) to upload, as patterns or structured entries",
 				Sources: cli.NewValueSourceChain(
 					cli.EnvVar("PARAMETER_SOURCES"),
 					cli.EnvVar("ARTIFACTORY_SOURCES"),
@@ -377,6 +895,36 @@ func main() {
 					cli.File("/vela/secrets/artifactory/sources"),
 				),
 			},
+			&cli.StringFlag{
+				Name:  "upload.spec",
+				Usage: "path to a YAML or JSON file in the workspace listing the files to upload",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_SPEC"),
+					cli.EnvVar("ARTIFACTORY_SPEC"),
+					cli.File("/vela/parameters/artifactory/spec"),
+					cli.File("/vela/secrets/artifactory/spec"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "upload.layout",
+				Usage: "template for the target path of each artifact, relative to the path",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_LAYOUT"),
+					cli.EnvVar("ARTIFACTORY_LAYOUT"),
+					cli.File("/vela/parameters/artifactory/layout"),
+					cli.File("/vela/secrets/artifactory/layout"),
+				),
+			},
+			&cli.StringFlag{
+				Name:  "upload.layout_pattern",
+				Usage: "regular expression for capturing layout tokens from the name of each artifact",
+				Sources: cli.NewValueSourceChain(
+					cli.EnvVar("PARAMETER_LAYOUT_PATTERN"),
+					cli.EnvVar("ARTIFACTORY_LAYOUT_PATTERN"),
+					cli.File("/vela/parameters/artifactory/layout_pattern"),
+					cli.File("/vela/secrets/artifactory/layout_pattern"),
+				),
+			},
 			&cli.StringFlag{
 				Name:  "upload.build_props",
 				Usage: "build props to apply",
@@ -418,6 +966,14 @@ func run(_ context.Context, c *cli.Command) error {
 		logrus.SetLevel(logrus.InfoLevel)
 	}
 
+	// set the log format for the plugin
+	formatter, err := logFormatter(c.String("log.format"))
+	if err != nil {
+		return err
+	}
+
+	logrus.SetFormatter(formatter)
+
 	logrus.WithFields(logrus.Fields{
 		"code":     "https://github.com/go-vela/vela-artifactory",
 		"docs":     "https://go-vela.github.io/docs/plugins/registry/pipeline/artifactory",
@@ -435,17 +991,44 @@ func run(_ context.Context, c *cli.Command) error {
 	sanitizedPath := strings.TrimSpace(c.String("path"))
 	sanitizedCopyTarget := strings.TrimSpace(c.String("copy.target"))
 
+	// create the selection shared by actions operating on the artifact(s) in the path
+	selection := Selection{
+		Exclusions:   c.StringSlice("exclusions"),
+		IncludeProps: c.String("include_props"),
+		ExcludeProps: c.String("exclude_props"),
+		Aql:          strings.TrimSpace(c.String("aql")),
+		AqlFile:      strings.TrimSpace(c.String("aql_file")),
+		DryRun:       c.Bool("config.dry_run"),
+	}
+
+	// create the property assertion shared by actions verifying the properties of the artifact(s)
+	assertion := PropAssertion{
+		RequiredProps:  c.String("required_props"),
+		ForbiddenProps: c.String("forbidden_props"),
+	}
+
 	// create the plugin
 	p := &Plugin{
+		// assert-props configuration
+		AssertProps: &AssertProps{
+			Selection: selection,
+			Assert:    assertion,
+			Path:      sanitizedPath,
+			Recursive: c.Bool("recursive"),
+		},
 		// config configuration
 		Config: &Config{
-			Action:   c.String("config.action"),
-			Token:    c.String("config.token"),
-			APIKey:   c.String("config.api_key"),
-			DryRun:   c.Bool("config.dry_run"),
-			Password: c.String("config.password"),
-			URL:      c.String("config.url"),
-			Username: c.String("config.username"),
+			Action:       c.String("config.action"),
+			AuthMethod:   c.String("config.auth_method"),
+			Token:        c.String("config.token"),
+			APIKey:       c.String("config.api_key"),
+			OIDCToken:    c.String("config.oidc_token"),
+			OIDCProvider: c.String("config.oidc_provider"),
+			Preflight:    c.Bool("config.preflight"),
+			DryRun:       c.Bool("config.dry_run"),
+			Password:     c.String("config.password"),
+			URL:          c.String("config.url"),
+			Username:     c.String("config.username"),
 			// http client configuration
 			Client: &Client{
 				Retries:            c.Int("client.retries"),
@@ -453,20 +1036,32 @@ func run(_ context.Context, c *cli.Command) error {
 				CertPath:           c.String("client.cert"),
 				CertKeyPath:        c.String("client.cert_key"),
 				InsecureTLS:        c.Bool("client.insecure_tls"),
+				TraceFile:          strings.TrimSpace(c.String("client.trace_file")),
 			},
 		},
 		// copy configuration
 		Copy: &Copy{
+			Selection: selection,
 			Flat:      c.Bool("copy.flat"),
 			Path:      sanitizedPath,
 			Recursive: c.Bool("recursive"),
 			Target:    sanitizedCopyTarget,
+			Assert:    assertion,
 		},
 		// delete configuration
 		Delete: &Delete{
+			Selection: selection,
 			Path:      sanitizedPath,
 			Recursive: c.Bool("recursive"),
 		},
+		// docker-cleanup configuration
+		DockerCleanup: &DockerCleanup{
+			Repo:    c.String("docker_cleanup.repo"),
+			Image:   c.String("docker_cleanup.image"),
+			Keep:    c.Int("docker_cleanup.keep"),
+			Protect: c.StringSlice("docker_cleanup.protect"),
+			DryRun:  c.Bool("config.dry_run"),
+		},
 		// docker-promote configuration
 		DockerPromote: &DockerPromote{
 			SourceRepo:           c.String("docker_promote.source_repo"),
@@ -475,28 +1070,99 @@ func run(_ context.Context, c *cli.Command) error {
 			TargetDockerRegistry: c.String("docker_promote.target_docker_registry"),
 			SourceTag:            c.String("docker_promote.source_tag"),
 			TargetTags:           c.StringSlice("docker_promote.target_tags"),
+			TagRules:             c.StringSlice("docker_promote.tag_rules"),
+			TagFilter:            c.String("docker_promote.tag_filter"),
+			TagFilterType:        c.String("docker_promote.tag_filter_type"),
+			KeepTagName:          c.Bool("docker_promote.keep_tag_name"),
+			RawImages:            c.String("docker_promote.images"),
+			Concurrency:          c.Int("docker_promote.concurrency"),
+			Rollback:             c.Bool("docker_promote.rollback"),
+			DryRun:               c.Bool("config.dry_run"),
 			Copy:                 c.Bool("docker_promote.copy"),
 			PromoteProperty:      c.Bool("docker_promote.props"),
+			RawProps:             c.String("docker_promote.properties"),
+			Assert:               assertion,
+		},
+		// get-prop configuration
+		GetProp: &GetProp{
+			Path:     sanitizedPath,
+			Keys:     c.StringSlice("get_prop.keys"),
+			Prefix:   c.String("get_prop.prefix"),
+			Output:   strings.TrimSpace(c.String("get_prop.output")),
+			Required: c.Bool("get_prop.required"),
+		},
+		// helm-publish configuration
+		HelmPublish: &HelmPublish{
+			Path:      sanitizedPath,
+			Sources:   c.StringSlice("helm_publish.sources"),
+			Overwrite: c.Bool("helm_publish.overwrite"),
+			DryRun:    c.Bool("config.dry_run"),
+		},
+		// maven-deploy configuration
+		MavenDeploy: &MavenDeploy{
+			Repo:       c.String("maven_deploy.repo"),
+			POM:        strings.TrimSpace(c.String("maven_deploy.pom")),
+			GroupID:    c.String("maven_deploy.group_id"),
+			ArtifactID: c.String("maven_deploy.artifact_id"),
+			Version:    c.String("maven_deploy.version"),
+			Packaging:  c.String("maven_deploy.packaging"),
+			Artifact:   strings.TrimSpace(c.String("maven_deploy.artifact")),
+			SourcesJar: strings.TrimSpace(c.String("maven_deploy.sources_jar")),
+			JavadocJar: strings.TrimSpace(c.String("maven_deploy.javadoc_jar")),
+			DryRun:     c.Bool("config.dry_run"),
+		},
+		// move configuration
+		Move: &Move{
+			Selection: selection,
+			Flat:      c.Bool("move.flat"),
+			Path:      sanitizedPath,
+			Recursive: c.Bool("recursive"),
+			Target:    strings.TrimSpace(c.String("move.target")),
+			Assert:    assertion,
+		},
+		// oci-push configuration
+		OCIPush: &OCIPush{
+			Source:     strings.TrimSpace(c.String("oci_push.source")),
+			RefName:    c.String("oci_push.ref_name"),
+			TargetRepo: c.String("oci_push.target_repo"),
+			Image:      c.String("oci_push.image"),
+			Tags:       c.StringSlice("oci_push.tags"),
+			RawProps:   c.String("oci_push.props"),
+			DryRun:     c.Bool("config.dry_run"),
+		},
+		// search configuration
+		Search: &Search{
+			Selection: selection,
+			Path:      sanitizedPath,
+			Recursive: c.Bool("recursive"),
 		},
 		// set-prop configuration
 		SetProp: &SetProp{
-			Path:     sanitizedPath,
-			RawProps: c.String("set_prop.props"),
+			Selection: selection,
+			Path:      sanitizedPath,
+			RawProps:  c.String("set_prop.props"),
+			Recursive: c.Bool("recursive"),
+			Scope:     c.String("set_prop.scope"),
 		},
 		// upload configuration
 		Upload: &Upload{
-			Flat:        c.Bool("upload.flat"),
-			IncludeDirs: c.Bool("upload.include_dirs"),
-			Recursive:   c.Bool("recursive"),
-			Regexp:      c.Bool("upload.regexp"),
-			Path:        sanitizedPath,
-			Sources:     c.StringSlice("upload.sources"),
-			BuildProps:  c.String("upload.build_props"),
+			Flat:          c.Bool("upload.flat"),
+			IncludeDirs:   c.Bool("upload.include_dirs"),
+			Recursive:     c.Bool("recursive"),
+			Regexp:        c.Bool("upload.regexp"),
+			Path:          sanitizedPath,
+			Sources:       c.StringSlice("upload.sources"),
+			Exclusions:    c.StringSlice("exclusions"),
+			Spec:          strings.TrimSpace(c.String("upload.spec")),
+			BuildProps:    c.String("upload.build_props"),
+			Layout:        c.String("upload.layout"),
+			LayoutPattern: c.String("upload.layout_pattern"),
+			DryRun:        c.Bool("config.dry_run"),
 		},
 	}
 
 	// validate the plugin
-	err := p.Validate()
+	err = p.Validate()
 	if err != nil {
 		return err
 	}
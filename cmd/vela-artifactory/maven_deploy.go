@@ -0,0 +1,624 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"crypto/md5"  //nolint:gosec // md5 checksums are required by Maven repositories
+	"crypto/sha1" //nolint:gosec // sha1 checksums are required by Maven repositories
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	mavenDeployAction = "maven-deploy"
+
+	// mavenMetadataFile is the name of the metadata file in a Maven repository.
+	mavenMetadataFile = "maven-metadata.xml"
+	// mavenSnapshotSuffix is the suffix of a Maven snapshot version.
+	mavenSnapshotSuffix = "-SNAPSHOT"
+)
+
+var (
+	// mavenIDRe matches a valid Maven group or artifact ID.
+	mavenIDRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	// mavenVersionRe matches a valid Maven version.
+	mavenVersionRe = regexp.MustCompile(`^[A-Za-z0-9_.+-]+$`)
+)
+
+// MavenDeploy represents the plugin configuration for deploying Maven artifacts.
+type MavenDeploy struct {
+	// Repo is the Maven repository in Artifactory to deploy to
+	Repo string
+	// POM is the path to the POM for the artifact (generated from the coordinates if empty)
+	POM string
+	// GroupID is the group ID of the artifact (read from the POM if empty)
+	GroupID string
+	// ArtifactID is the artifact ID of the artifact (read from the POM if empty)
+	ArtifactID string
+	// Version is the version of the artifact (read from the POM if empty)
+	Version string
+	// Packaging is the packaging of the artifact (read from the POM if empty)
+	Packaging string
+	// Artifact is the path to the main artifact file
+	Artifact string
+	// SourcesJar is the path to the sources jar for the artifact
+	SourcesJar string
+	// JavadocJar is the path to the javadoc jar for the artifact
+	JavadocJar string
+	// DryRun is a flag to set to log the files to deploy without deploying them
+	DryRun bool
+}
+
+// mavenPOM represents the fields read from a Maven POM.
+type mavenPOM struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Packaging  string `xml:"packaging"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+}
+
+// mavenCoordinates represents the coordinates of a Maven artifact.
+type mavenCoordinates struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Packaging  string
+}
+
+// mavenMetadata represents a maven-metadata.xml file.
+//
+// https://maven.apache.org/ref/current/maven-repository-metadata/repository-metadata.html
+type mavenMetadata struct {
+	XMLName      xml.Name        `xml:"metadata"`
+	ModelVersion string          `xml:"modelVersion,attr,omitempty"`
+	GroupID      string          `xml:"groupId"`
+	ArtifactID   string          `xml:"artifactId"`
+	Version      string          `xml:"version,omitempty"`
+	Versioning   mavenVersioning `xml:"versioning"`
+}
+
+// mavenVersioning represents the versioning of a maven-metadata.xml file.
+type mavenVersioning struct {
+	Latest           string                 `xml:"latest,omitempty"`
+	Release          string                 `xml:"release,omitempty"`
+	Snapshot         *mavenSnapshot         `xml:"snapshot,omitempty"`
+	Versions         []string               `xml:"versions>version,omitempty"`
+	LastUpdated      string                 `xml:"lastUpdated,omitempty"`
+	SnapshotVersions []mavenSnapshotVersion `xml:"snapshotVersions>snapshotVersion,omitempty"`
+}
+
+// mavenSnapshot represents the latest snapshot in a maven-metadata.xml file.
+type mavenSnapshot struct {
+	Timestamp   string `xml:"timestamp"`
+	BuildNumber int    `xml:"buildNumber"`
+}
+
+// mavenSnapshotVersion represents a file of a snapshot in a maven-metadata.xml file.
+type mavenSnapshotVersion struct {
+	Classifier string `xml:"classifier,omitempty"`
+	Extension  string `xml:"extension"`
+	Value      string `xml:"value"`
+	Updated    string `xml:"updated"`
+}
+
+// mavenFile represents a file to deploy for a Maven artifact.
+type mavenFile struct {
+	// Classifier is the classifier of the file (e.g. sources)
+	Classifier string
+	// Extension is the extension of the file (e.g. jar)
+	Extension string
+	// Source is the path to the file to deploy
+	Source string
+	// Content is the content to deploy when there is no source
+	Content []byte
+}
+
+// mavenChecksum represents a checksum uploaded alongside a file in a Maven repository.
+type mavenChecksum struct {
+	// Extension is the extension of the checksum file
+	Extension string
+	// Header is the Artifactory header for deploying with the checksum
+	Header string
+	// Value is the hex encoded checksum
+	Value string
+}
+
+// Exec formats and runs the commands for deploying Maven artifacts in Artifactory.
+func (m *MavenDeploy) Exec(cli artifactory.ArtifactoryServicesManager) error {
+	logrus.Trace("running maven-deploy with provided configuration")
+
+	start := time.Now()
+
+	coords, err := m.Coordinates()
+	if err != nil {
+		return err
+	}
+
+	logger := actionLogger(mavenDeployAction, path.Join(m.Repo, coords.dir()))
+	now := time.Now().UTC()
+
+	files, err := m.files(coords)
+	if err != nil {
+		return err
+	}
+
+	// the files are deployed through raw API calls the client dry run does not cover
+	if m.DryRun {
+		m.logPlan(logger, start, coords, files)
+
+		return nil
+	}
+
+	// snapshot files are deployed with a unique timestamped version
+	fileVersion := coords.Version
+
+	var snapshot *mavenMetadata
+
+	if coords.isSnapshot() {
+		snapshot, err = m.metadata(cli, path.Join(coords.dir(), mavenMetadataFile))
+		if err != nil {
+			return err
+		}
+
+		buildNumber := 1
+		if snapshot.Versioning.Snapshot != nil {
+			buildNumber = snapshot.Versioning.Snapshot.BuildNumber + 1
+		}
+
+		timestamp := now.Format("20060102.150405")
+		fileVersion = fmt.Sprintf("%s-%s-%d", strings.TrimSuffix(coords.Version, mavenSnapshotSuffix), timestamp, buildNumber)
+
+		snapshot.ModelVersion = "1.1.0"
+		snapshot.GroupID = coords.GroupID
+		snapshot.ArtifactID = coords.ArtifactID
+		snapshot.Version = coords.Version
+		snapshot.Versioning.Snapshot = &mavenSnapshot{Timestamp: timestamp, BuildNumber: buildNumber}
+		snapshot.Versioning.LastUpdated = now.Format("20060102150405")
+
+		for _, file := range files {
+			snapshot.Versioning.setSnapshotVersion(mavenSnapshotVersion{
+				Classifier: file.Classifier,
+				Extension:  file.Extension,
+				Value:      fileVersion,
+				Updated:    snapshot.Versioning.LastUpdated,
+			})
+		}
+	}
+
+	// deploy each file of the artifact with its checksums
+	for _, file := range files {
+		target := path.Join(coords.dir(), coords.fileName(fileVersion, file.Classifier, file.Extension))
+
+		err = m.deploy(cli, target, file)
+		if err != nil {
+			return fmt.Errorf("unable to deploy %s: %w", target, err)
+		}
+
+		logger.WithField("artifact", path.Join(m.Repo, target)).Infof("Deployed %s", path.Join(m.Repo, target))
+	}
+
+	// deploy the metadata for the snapshot version
+	if snapshot != nil {
+		err = m.deployMetadata(cli, path.Join(coords.dir(), mavenMetadataFile), snapshot)
+		if err != nil {
+			return err
+		}
+	}
+
+	// deploy the metadata for the artifact with the version
+	metadataPath := path.Join(coords.artifactDir(), mavenMetadataFile)
+
+	metadata, err := m.metadata(cli, metadataPath)
+	if err != nil {
+		return err
+	}
+
+	metadata.GroupID = coords.GroupID
+	metadata.ArtifactID = coords.ArtifactID
+	metadata.Version = ""
+	metadata.Versioning.Latest = coords.Version
+	metadata.Versioning.LastUpdated = now.Format("20060102150405")
+
+	if !coords.isSnapshot() {
+		metadata.Versioning.Release = coords.Version
+	}
+
+	if !slices.Contains(metadata.Versioning.Versions, coords.Version) {
+		metadata.Versioning.Versions = append(metadata.Versioning.Versions, coords.Version)
+	}
+
+	err = m.deployMetadata(cli, metadataPath, metadata)
+	if err != nil {
+		return err
+	}
+
+	withDuration(logger, start).WithField("success", len(files)).
+		Infof("Deployed %d file(s) for %s:%s:%s", len(files), coords.GroupID, coords.ArtifactID, fileVersion)
+
+	return nil
+}
+
+// logPlan logs the coordinates and the paths of the files and metadata to deploy.
+//
+// The snapshot files are logged with the snapshot version, since the
+// timestamped version is resolved from the metadata in the repository.
+func (m *MavenDeploy) logPlan(logger *logrus.Entry, start time.Time, coords *mavenCoordinates, files []*mavenFile) {
+	logger.Infof("Deploying %s:%s:%s with packaging %s", coords.GroupID, coords.ArtifactID, coords.Version, coords.Packaging)
+
+	for _, file := range files {
+		target := path.Join(m.Repo, coords.dir(), coords.fileName(coords.Version, file.Classifier, file.Extension))
+
+		source := file.Source
+		if len(source) == 0 {
+			source = "generated POM"
+		}
+
+		logger.WithField("artifact", target).Infof("  [dry run] %s -> %s", source, target)
+	}
+
+	metadata := []string{path.Join(m.Repo, coords.artifactDir(), mavenMetadataFile)}
+
+	if coords.isSnapshot() {
+		metadata = append(metadata, path.Join(m.Repo, coords.dir(), mavenMetadataFile))
+	}
+
+	for _, file := range metadata {
+		logger.WithField("artifact", file).Infof("  [dry run] %s", file)
+	}
+
+	withDuration(logger, start).Infof("Dry run enabled, skipping deploy of %d file(s) for %s:%s:%s", len(files), coords.GroupID, coords.ArtifactID, coords.Version)
+}
+
+// Coordinates returns the coordinates of the artifact, using the values
+// read from the POM for any coordinates that are not provided.
+func (m *MavenDeploy) Coordinates() (*mavenCoordinates, error) {
+	coords := &mavenCoordinates{
+		GroupID:    m.GroupID,
+		ArtifactID: m.ArtifactID,
+		Version:    m.Version,
+		Packaging:  m.Packaging,
+	}
+
+	if len(m.POM) > 0 {
+		pom, err := readMavenPOM(m.POM)
+		if err != nil {
+			return nil, err
+		}
+
+		// the group ID and version are inherited from the parent when not provided
+		coords.GroupID = firstNonEmpty(coords.GroupID, pom.GroupID, pom.Parent.GroupID)
+		coords.ArtifactID = firstNonEmpty(coords.ArtifactID, pom.ArtifactID)
+		coords.Version = firstNonEmpty(coords.Version, pom.Version, pom.Parent.Version)
+		coords.Packaging = firstNonEmpty(coords.Packaging, pom.Packaging)
+	}
+
+	coords.Packaging = firstNonEmpty(coords.Packaging, "jar")
+
+	// verify the coordinates are valid for the repository layout
+	if !mavenIDRe.MatchString(coords.GroupID) {
+		return nil, fmt.Errorf("invalid maven-deploy group ID %q provided", coords.GroupID)
+	}
+
+	if !mavenIDRe.MatchString(coords.ArtifactID) {
+		return nil, fmt.Errorf("invalid maven-deploy artifact ID %q provided", coords.ArtifactID)
+	}
+
+	if !mavenVersionRe.MatchString(coords.Version) {
+		return nil, fmt.Errorf("invalid maven-deploy version %q provided", coords.Version)
+	}
+
+	return coords, nil
+}
+
+// files returns the files to deploy for the artifact.
+func (m *MavenDeploy) files(coords *mavenCoordinates) ([]*mavenFile, error) {
+	var files []*mavenFile
+
+	if len(m.Artifact) > 0 {
+		extension := strings.TrimPrefix(filepath.Ext(m.Artifact), ".")
+		if len(extension) == 0 {
+			extension = coords.Packaging
+		}
+
+		files = append(files, &mavenFile{Extension: extension, Source: m.Artifact})
+	}
+
+	if len(m.SourcesJar) > 0 {
+		files = append(files, &mavenFile{Classifier: "sources", Extension: "jar", Source: m.SourcesJar})
+	}
+
+	if len(m.JavadocJar) > 0 {
+		files = append(files, &mavenFile{Classifier: "javadoc", Extension: "jar", Source: m.JavadocJar})
+	}
+
+	if len(m.POM) > 0 {
+		return append(files, &mavenFile{Extension: "pom", Source: m.POM}), nil
+	}
+
+	// generate a minimal POM for the coordinates
+	pom, err := coords.pom()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(files, &mavenFile{Extension: "pom", Content: pom}), nil
+}
+
+// metadata returns the maven-metadata.xml at the path in the repository,
+// or empty metadata when the file does not exist.
+func (m *MavenDeploy) metadata(cli artifactory.ArtifactoryServicesManager, file string) (*mavenMetadata, error) {
+	metadata := new(mavenMetadata)
+
+	// send API call to download the metadata
+	resp, body, err := apiGet(cli, path.Join(m.Repo, file))
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return metadata, nil
+	default:
+		return nil, fmt.Errorf("unable to download %s: %s", file, resp.Status)
+	}
+
+	err = xml.Unmarshal(body, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", file, err)
+	}
+
+	return metadata, nil
+}
+
+// deployMetadata deploys the maven-metadata.xml to the path in the repository.
+func (m *MavenDeploy) deployMetadata(cli artifactory.ArtifactoryServicesManager, file string, metadata *mavenMetadata) error {
+	content, err := xml.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = m.deploy(cli, file, &mavenFile{Content: append([]byte(xml.Header), content...)})
+	if err != nil {
+		return fmt.Errorf("unable to deploy %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// deploy uploads the file, along with its checksum files, to the path in the repository.
+func (m *MavenDeploy) deploy(cli artifactory.ArtifactoryServicesManager, file string, source *mavenFile) error {
+	var content io.ReadSeeker = bytes.NewReader(source.Content)
+
+	if len(source.Source) > 0 {
+		f, err := os.Open(source.Source)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		content = f
+	}
+
+	checksums, size, err := mavenChecksums(content)
+	if err != nil {
+		return err
+	}
+
+	_, err = content.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	serviceDetails := cli.GetConfig().GetServiceDetails()
+	u := serviceDetails.GetUrl() + path.Join(m.Repo, file)
+
+	headers := make(map[string]string)
+	for _, checksum := range checksums {
+		headers[checksum.Header] = checksum.Value
+	}
+
+	logrus.Tracef("deploying %s (%d bytes) to %s", file, size, m.Repo)
+
+	// send API call to upload the file with its checksums
+	_, _, err = cli.Client().UploadFileFromReader(content, u, mavenDetails(cli, headers), size)
+	if err != nil {
+		return err
+	}
+
+	// send API calls to upload the checksum files
+	for _, checksum := range checksums {
+		resp, body, err := cli.Client().SendPut(u+"."+checksum.Extension, []byte(checksum.Value), mavenDetails(cli, nil))
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unable to deploy %s checksum: %s %s", checksum.Extension, resp.Status, string(body))
+		}
+	}
+
+	return nil
+}
+
+// mavenDetails returns the HTTP details with the headers using the credentials configured for the client.
+func mavenDetails(cli artifactory.ArtifactoryServicesManager, headers map[string]string) *httputils.HttpClientDetails {
+	details := cli.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+
+	if details.Headers == nil {
+		details.Headers = make(map[string]string)
+	}
+
+	for key, value := range headers {
+		details.Headers[key] = value
+	}
+
+	return &details
+}
+
+// mavenChecksums returns the checksums and size of the content.
+func mavenChecksums(r io.Reader) ([]*mavenChecksum, int64, error) {
+	md5Hash := md5.New()   //nolint:gosec // md5 checksums are required by Maven repositories
+	sha1Hash := sha1.New() //nolint:gosec // sha1 checksums are required by Maven repositories
+	sha256Hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(md5Hash, sha1Hash, sha256Hash), r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return []*mavenChecksum{
+		{Extension: "md5", Header: "X-Checksum-Md5", Value: hex.EncodeToString(md5Hash.Sum(nil))},
+		{Extension: "sha1", Header: "X-Checksum-Sha1", Value: hex.EncodeToString(sha1Hash.Sum(nil))},
+		{Extension: "sha256", Header: "X-Checksum-Sha256", Value: hex.EncodeToString(sha256Hash.Sum(nil))},
+	}, size, nil
+}
+
+// readMavenPOM reads the coordinates from the POM.
+func readMavenPOM(file string) (*mavenPOM, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read POM %s: %w", file, err)
+	}
+
+	pom := new(mavenPOM)
+
+	err = xml.Unmarshal(data, pom)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse POM %s: %w", file, err)
+	}
+
+	return pom, nil
+}
+
+// setSnapshotVersion adds the snapshot version, replacing any existing
+// version with the same classifier and extension.
+func (v *mavenVersioning) setSnapshotVersion(version mavenSnapshotVersion) {
+	for i, existing := range v.SnapshotVersions {
+		if existing.Classifier == version.Classifier && existing.Extension == version.Extension {
+			v.SnapshotVersions[i] = version
+
+			return
+		}
+	}
+
+	v.SnapshotVersions = append(v.SnapshotVersions, version)
+}
+
+// isSnapshot reports whether the version is a snapshot version.
+func (c *mavenCoordinates) isSnapshot() bool {
+	return strings.HasSuffix(c.Version, mavenSnapshotSuffix)
+}
+
+// artifactDir returns the path to the artifact in the repository layout.
+func (c *mavenCoordinates) artifactDir() string {
+	return path.Join(strings.ReplaceAll(c.GroupID, ".", "/"), c.ArtifactID)
+}
+
+// dir returns the path to the version of the artifact in the repository layout.
+func (c *mavenCoordinates) dir() string {
+	return path.Join(c.artifactDir(), c.Version)
+}
+
+// fileName returns the name of a file for the version of the artifact in the repository layout.
+func (c *mavenCoordinates) fileName(version, classifier, extension string) string {
+	if len(classifier) > 0 {
+		return fmt.Sprintf("%s-%s-%s.%s", c.ArtifactID, version, classifier, extension)
+	}
+
+	return fmt.Sprintf("%s-%s.%s", c.ArtifactID, version, extension)
+}
+
+// pom returns a minimal POM for the coordinates.
+func (c *mavenCoordinates) pom() ([]byte, error) {
+	pom := struct {
+		XMLName      xml.Name `xml:"project"`
+		Namespace    string   `xml:"xmlns,attr"`
+		ModelVersion string   `xml:"modelVersion"`
+		GroupID      string   `xml:"groupId"`
+		ArtifactID   string   `xml:"artifactId"`
+		Version      string   `xml:"version"`
+		Packaging    string   `xml:"packaging"`
+	}{
+		Namespace:    "http://maven.apache.org/POM/4.0.0",
+		ModelVersion: "4.0.0",
+		GroupID:      c.GroupID,
+		ArtifactID:   c.ArtifactID,
+		Version:      c.Version,
+		Packaging:    c.Packaging,
+	}
+
+	content, err := xml.MarshalIndent(pom, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), content...), nil
+}
+
+// firstNonEmpty returns the first value that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if len(value) > 0 {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Validate verifies the MavenDeploy is properly configured.
+func (m *MavenDeploy) Validate() error {
+	logrus.Trace("validating maven-deploy plugin configuration")
+
+	// verify a repo is provided
+	if len(m.Repo) == 0 {
+		return fmt.Errorf("no maven-deploy repository provided")
+	}
+
+	// verify the coordinates are provided or read from the POM
+	coords, err := m.Coordinates()
+	if err != nil {
+		return err
+	}
+
+	// verify an artifact is provided unless only deploying a POM
+	if len(m.Artifact) == 0 && coords.Packaging != "pom" {
+		return fmt.Errorf("no maven-deploy artifact provided for %s packaging", coords.Packaging)
+	}
+
+	// verify the files to deploy exist
+	for _, file := range []string{m.Artifact, m.SourcesJar, m.JavadocJar} {
+		if len(file) == 0 {
+			continue
+		}
+
+		_, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("invalid maven-deploy file provided: %w", err)
+		}
+	}
+
+	return nil
+}
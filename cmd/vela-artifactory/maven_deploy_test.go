@@ -0,0 +1,380 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+// mavenServer returns a mock server that records the content deployed to the Maven repository.
+func mavenServer(t *testing.T) (*httptest.Server, func() map[string]string) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		deployed = make(map[string]string)
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+
+			mu.Lock()
+			deployed[r.URL.Path] = string(body)
+			mu.Unlock()
+
+			r.Body = io.NopCloser(strings.NewReader(string(body)))
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s, func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return deployed
+	}
+}
+
+// mavenPlugin returns a plugin configured to deploy to the server.
+func mavenPlugin(url string, m *MavenDeploy) *Plugin {
+	return &Plugin{
+		Config: &Config{
+			Action:   "maven-deploy",
+			URL:      url,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		MavenDeploy: m,
+	}
+}
+
+// writeFile writes the content to a file in the directory and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	file := filepath.Join(dir, name)
+
+	err := os.WriteFile(file, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	return file
+}
+
+func TestArtifactory_MavenDeploy_Exec(t *testing.T) {
+	// setup types
+	s, deployed := mavenServer(t)
+
+	dir := t.TempDir()
+
+	p := mavenPlugin(s.URL, &MavenDeploy{
+		Repo:       "maven",
+		GroupID:    "com.example",
+		ArtifactID: "app",
+		Version:    "1.0.0",
+		Artifact:   writeFile(t, dir, "app.jar", "jar"),
+		SourcesJar: writeFile(t, dir, "app-sources.jar", "sources"),
+		JavadocJar: writeFile(t, dir, "app-javadoc.jar", "javadoc"),
+	})
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	got := deployed()
+
+	for _, file := range []string{
+		"/maven/com/example/app/1.0.0/app-1.0.0.jar",
+		"/maven/com/example/app/1.0.0/app-1.0.0-sources.jar",
+		"/maven/com/example/app/1.0.0/app-1.0.0-javadoc.jar",
+		"/maven/com/example/app/1.0.0/app-1.0.0.pom",
+		"/maven/com/example/app/maven-metadata.xml",
+	} {
+		for _, suffix := range []string{"", ".md5", ".sha1", ".sha256"} {
+			if _, ok := got[file+suffix]; !ok {
+				t.Errorf("Exec did not deploy %s", file+suffix)
+			}
+		}
+	}
+
+	if got["/maven/com/example/app/1.0.0/app-1.0.0.jar.sha1"] != "f92e777f4341930bad9b2422283c4680d00dbc06" {
+		t.Errorf("Exec deployed sha1 %s", got["/maven/com/example/app/1.0.0/app-1.0.0.jar.sha1"])
+	}
+
+	if !strings.Contains(got["/maven/com/example/app/1.0.0/app-1.0.0.pom"], "<artifactId>app</artifactId>") {
+		t.Errorf("Exec deployed POM %s", got["/maven/com/example/app/1.0.0/app-1.0.0.pom"])
+	}
+
+	if !strings.Contains(got["/maven/com/example/app/maven-metadata.xml"], "<release>1.0.0</release>") {
+		t.Errorf("Exec deployed metadata %s", got["/maven/com/example/app/maven-metadata.xml"])
+	}
+}
+
+func TestArtifactory_MavenDeploy_Exec_Snapshot(t *testing.T) {
+	// setup types
+	s, deployed := mavenServer(t)
+
+	dir := t.TempDir()
+
+	p := mavenPlugin(s.URL, &MavenDeploy{
+		Repo:       "maven",
+		GroupID:    "com.existing",
+		ArtifactID: "app",
+		Version:    "1.1.0-SNAPSHOT",
+		Artifact:   writeFile(t, dir, "app.jar", "jar"),
+	})
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	jarRe := regexp.MustCompile(`^/maven/com/existing/app/1\.1\.0-SNAPSHOT/app-1\.1\.0-\d{8}\.\d{6}-4\.jar$`)
+
+	var found bool
+
+	for file := range deployed() {
+		if jarRe.MatchString(file) {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Exec did not deploy timestamped snapshot jar: %v", deployed())
+	}
+
+	snapshot := deployed()["/maven/com/existing/app/1.1.0-SNAPSHOT/maven-metadata.xml"]
+
+	for _, want := range []string{"<buildNumber>4</buildNumber>", "<version>1.1.0-SNAPSHOT</version>", "<extension>pom</extension>"} {
+		if !strings.Contains(snapshot, want) {
+			t.Errorf("Exec deployed snapshot metadata without %s: %s", want, snapshot)
+		}
+	}
+
+	metadata := deployed()["/maven/com/existing/app/maven-metadata.xml"]
+
+	for _, want := range []string{"<version>0.9.0</version>", "<version>1.1.0-SNAPSHOT</version>", "<release>1.0.0</release>", "<latest>1.1.0-SNAPSHOT</latest>"} {
+		if !strings.Contains(metadata, want) {
+			t.Errorf("Exec deployed artifact metadata without %s: %s", want, metadata)
+		}
+	}
+}
+
+func TestArtifactory_MavenDeploy_Exec_DryRun(t *testing.T) {
+	// setup types
+	s, requests := requestServer(t)
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	dir := t.TempDir()
+
+	p := mavenPlugin(s.URL, &MavenDeploy{
+		Repo:       "maven",
+		GroupID:    "com.example",
+		ArtifactID: "app",
+		Version:    "1.1.0-SNAPSHOT",
+		Artifact:   writeFile(t, dir, "app.jar", "jar"),
+		DryRun:     true,
+	})
+	p.Config.DryRun = true
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	for _, request := range requests() {
+		if strings.Contains(request, " /maven/") {
+			t.Errorf("Exec sent %s in dry run", request)
+		}
+	}
+
+	var got []string
+
+	for _, entry := range hook.AllEntries() {
+		if strings.HasPrefix(entry.Message, "  [dry run] ") {
+			got = append(got, strings.ReplaceAll(entry.Message, dir+string(filepath.Separator), ""))
+		}
+	}
+
+	want := []string{
+		"  [dry run] app.jar -> maven/com/example/app/1.1.0-SNAPSHOT/app-1.1.0-SNAPSHOT.jar",
+		"  [dry run] generated POM -> maven/com/example/app/1.1.0-SNAPSHOT/app-1.1.0-SNAPSHOT.pom",
+		"  [dry run] maven/com/example/app/maven-metadata.xml",
+		"  [dry run] maven/com/example/app/1.1.0-SNAPSHOT/maven-metadata.xml",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Exec logged %v, want %v", got, want)
+	}
+}
+
+func TestArtifactory_MavenDeploy_Coordinates(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	pom := writeFile(t, dir, "pom.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>2.0.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <packaging>war</packaging>
+  <dependencies>
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>lib</artifactId>
+      <version>9.9.9</version>
+    </dependency>
+  </dependencies>
+</project>`)
+
+	// setup tests
+	tests := []struct {
+		name    string
+		m       *MavenDeploy
+		want    *mavenCoordinates
+		wantErr bool
+	}{
+		{
+			name: "coordinates",
+			m:    &MavenDeploy{GroupID: "com.example", ArtifactID: "app", Version: "1.0.0"},
+			want: &mavenCoordinates{GroupID: "com.example", ArtifactID: "app", Version: "1.0.0", Packaging: "jar"},
+		},
+		{
+			name: "pom with parent",
+			m:    &MavenDeploy{POM: pom},
+			want: &mavenCoordinates{GroupID: "com.example", ArtifactID: "app", Version: "2.0.0", Packaging: "war"},
+		},
+		{
+			name: "pom with overrides",
+			m:    &MavenDeploy{POM: pom, Version: "2.1.0-SNAPSHOT"},
+			want: &mavenCoordinates{GroupID: "com.example", ArtifactID: "app", Version: "2.1.0-SNAPSHOT", Packaging: "war"},
+		},
+		{
+			name:    "unresolved property",
+			m:       &MavenDeploy{GroupID: "com.example", ArtifactID: "app", Version: "${revision}"},
+			wantErr: true,
+		},
+		{
+			name:    "missing pom",
+			m:       &MavenDeploy{POM: filepath.Join(dir, "missing.xml")},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.m.Coordinates()
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Coordinates should have returned err")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Coordinates returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Coordinates is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_MavenDeploy_Validate(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	jar := writeFile(t, dir, "app.jar", "jar")
+
+	// setup tests
+	tests := []struct {
+		name    string
+		m       *MavenDeploy
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			m:    &MavenDeploy{Repo: "maven", GroupID: "com.example", ArtifactID: "app", Version: "1.0.0", Artifact: jar},
+		},
+		{
+			name: "pom packaging",
+			m:    &MavenDeploy{Repo: "maven", GroupID: "com.example", ArtifactID: "app", Version: "1.0.0", Packaging: "pom"},
+		},
+		{
+			name:    "no repo",
+			m:       &MavenDeploy{GroupID: "com.example", ArtifactID: "app", Version: "1.0.0", Artifact: jar},
+			wantErr: true,
+		},
+		{
+			name:    "no group id",
+			m:       &MavenDeploy{Repo: "maven", ArtifactID: "app", Version: "1.0.0", Artifact: jar},
+			wantErr: true,
+		},
+		{
+			name:    "no artifact",
+			m:       &MavenDeploy{Repo: "maven", GroupID: "com.example", ArtifactID: "app", Version: "1.0.0"},
+			wantErr: true,
+		},
+		{
+			name:    "missing sources jar",
+			m:       &MavenDeploy{Repo: "maven", GroupID: "com.example", ArtifactID: "app", Version: "1.0.0", Artifact: jar, SourcesJar: filepath.Join(dir, "missing.jar")},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.m.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
@@ -3,7 +3,10 @@
 package mock
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -16,6 +19,8 @@ const (
 	APIKey                      = "superSecretAPIKey"
 	Password                    = "superSecretPassword"
 	Token                       = "superSecretToken"
+	OIDCToken                   = "superSecretOIDCToken"
+	OIDCProvider                = "vela"
 )
 
 // Handlers returns an http.Handler group that is capable of handling
@@ -25,35 +30,192 @@ func Handlers() *gin.Engine {
 
 	e := gin.New()
 
+	e.GET("/api/system/ping", ping)
 	e.GET("/api/system/version", getVersion)
+	e.POST("/access/api/v1/oidc/token", exchangeOIDCToken)
+	e.GET("/api/repositories", getAllRepositories)
+	e.GET("/api/storage/:repo", getPermissions)
+	e.GET("/api/storage/:repo/*path", getStorage)
 	e.POST("/api/search/aql", search)
 	e.POST("/api/copy", copyArtifact)
-	e.DELETE("/", deleteArtifact)
+	e.POST("/api/copy/*path", copyArtifact)
+	e.POST("/api/move", moveArtifact)
+	e.POST("/api/move/*path", moveArtifact)
+	e.DELETE("/*path", deleteArtifact)
 	e.GET("/api/docker/:registry/v2/_catalog", getRepositories)
 	e.GET("/api/docker/:registry/v2/docker-dev/tags/list", getTags)
+	e.GET("/api/docker/:registry/v2/:image/manifests/:reference", getManifest)
 	e.POST("/api/docker/:registry/v2/promote", promoteImage)
+	e.HEAD("/api/docker/:registry/v2/:image/blobs/:digest", headBlob)
+	e.POST("/api/docker/:registry/v2/:image/blobs/uploads/", startUpload)
+	e.PUT("/api/docker/:registry/v2/:image/blobs/uploads/:uuid", completeUpload)
+	e.PUT("/api/docker/:registry/v2/:image/manifests/:reference", putManifest)
 	e.PUT("/api/storage", setProp)
+	e.PUT("/api/storage/*path", setProp)
 	e.PUT("/foo/bar", uploadFiles)
+	e.PUT("/helm/*path", uploadFiles)
+	e.POST("/api/helm/:repo/reindex", reindexHelm)
+	e.GET("/maven/*path", getMavenMetadata)
+	e.PUT("/maven/*path", uploadFiles)
 
 	return e
 }
 
+func ping(c *gin.Context) {
+	c.String(200, "OK")
+}
+
+func exchangeOIDCToken(c *gin.Context) {
+	body := make(map[string]string)
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil || body["subject_token"] != OIDCToken || body["provider_name"] != OIDCProvider {
+		c.JSON(401, "Invalid OIDC token")
+		return
+	}
+
+	c.JSON(200, map[string]interface{}{
+		"access_token": Token,
+		"username":     Username,
+	})
+}
+
+func getAllRepositories(c *gin.Context) {
+	c.JSON(200, []map[string]string{
+		{"key": "foo", "type": "LOCAL", "packageType": "generic"},
+		{"key": "docker", "type": "LOCAL", "packageType": "docker"},
+	})
+}
+
+func getPermissions(c *gin.Context) {
+	repo := c.Param("repo")
+
+	if strings.Contains(repo, "not-found") {
+		c.JSON(404, fmt.Sprintf("Repository %s does not exist", repo))
+		return
+	}
+
+	if strings.Contains(repo, "read-only") {
+		c.JSON(200, map[string]interface{}{
+			"principals": map[string]interface{}{
+				"users": map[string][]string{Username: {"r"}},
+			},
+		})
+
+		return
+	}
+
+	c.JSON(200, map[string]interface{}{
+		"principals": map[string]interface{}{
+			"users": map[string][]string{Username: {"r", "w", "d", "n"}},
+		},
+	})
+}
+
 func getVersion(c *gin.Context) {
 	c.String(200, loadFixture("mock/fixtures/version.json"))
 }
 
 func search(c *gin.Context) {
-	c.String(200, loadFixture("mock/fixtures/search.json"))
+	data, err := c.GetRawData()
+	if err != nil {
+		c.JSON(400, "Unable to read search")
+		return
+	}
+
+	query := string(data)
+
+	switch {
+	case strings.Contains(query, "manifest.json") && strings.Contains(query, "$ne"):
+		c.String(200, loadFixture("mock/fixtures/cleanup_references.json"))
+	case strings.Contains(query, "manifest.json"):
+		c.String(200, loadFixture("mock/fixtures/cleanup_manifests.json"))
+	case strings.Contains(query, "folders-local") && strings.Contains(query, `"type":"any"`):
+		c.String(200, loadFixture("mock/fixtures/search_folders.json"))
+	case strings.Contains(query, "folders-local"):
+		c.String(200, loadFixture("mock/fixtures/search_folder_files.json"))
+	case strings.Contains(query, "existing"):
+		c.String(200, loadFixture("mock/fixtures/search_item.json"))
+	case strings.Contains(query, "props-local"):
+		c.String(200, loadFixture("mock/fixtures/search_props.json"))
+	default:
+		c.String(200, loadFixture("mock/fixtures/search.json"))
+	}
 }
 
 func copyArtifact(c *gin.Context) {
 	c.JSON(200, "Copy ended successfully")
 }
 
+func moveArtifact(c *gin.Context) {
+	c.JSON(200, "Move ended successfully")
+}
+
 func deleteArtifact(c *gin.Context) {
 	c.JSON(204, "Delete ended successfully")
 }
 
+func getStorage(c *gin.Context) {
+	path := c.Param("repo") + c.Param("path")
+
+	// artifacts with existing in the path are already stored
+	if !strings.Contains(path, "existing") {
+		c.JSON(404, map[string]interface{}{
+			"errors": []map[string]interface{}{{"status": 404, "message": "Unable to find item"}},
+		})
+
+		return
+	}
+
+	// return the properties of the artifact when they are requested
+	if _, ok := c.GetQuery("properties"); ok {
+		if strings.Contains(path, "noprops") {
+			c.JSON(404, map[string]interface{}{
+				"errors": []map[string]interface{}{{"status": 404, "message": "No properties could be found."}},
+			})
+
+			return
+		}
+
+		c.String(200, loadFixture("mock/fixtures/item_props.json"))
+
+		return
+	}
+
+	c.JSON(200, map[string]interface{}{
+		"repo": c.Param("repo"),
+		"path": c.Param("path"),
+	})
+}
+
+func reindexHelm(c *gin.Context) {
+	repo := c.Param("repo")
+
+	if strings.Contains(repo, "not-found") {
+		c.JSON(404, fmt.Sprintf("Repository %s does not exist", repo))
+		return
+	}
+
+	c.JSON(200, fmt.Sprintf("Recalculating index for Helm repository %s scheduled to run", repo))
+}
+
+func getMavenMetadata(c *gin.Context) {
+	path := c.Param("path")
+
+	// metadata is only stored for artifacts with existing in the path
+	if !strings.Contains(path, "existing") || !strings.HasSuffix(path, "/maven-metadata.xml") {
+		c.JSON(404, fmt.Sprintf("Item %s does not exist", path))
+		return
+	}
+
+	if strings.Contains(path, "-SNAPSHOT/") {
+		c.Data(200, "application/xml", []byte(loadFixture("mock/fixtures/maven_snapshot_metadata.xml")))
+		return
+	}
+
+	c.Data(200, "application/xml", []byte(loadFixture("mock/fixtures/maven_metadata.xml")))
+}
+
 func setProp(c *gin.Context) {
 	c.JSON(204, "Property set successfully")
 }
@@ -86,6 +248,75 @@ func getTags(c *gin.Context) {
 	c.String(200, loadFixture("mock/fixtures/tags.json"))
 }
 
+func getManifest(c *gin.Context) {
+	registry := c.Param("registry")
+	image := c.Param("image")
+	reference := c.Param("reference")
+
+	// platform manifests are requested by digest
+	if strings.HasPrefix(reference, "sha256:") {
+		if strings.Contains(registry, "missing") {
+			c.JSON(404, fmt.Sprintf("Manifest %s does not exist", reference))
+			return
+		}
+
+		c.Data(200, "application/vnd.docker.distribution.manifest.v2+json", []byte(loadFixture("mock/fixtures/manifest.json")))
+
+		return
+	}
+
+	if strings.Contains(image, "multi-arch") {
+		c.Data(200, "application/vnd.docker.distribution.manifest.list.v2+json", []byte(loadFixture("mock/fixtures/manifest_list.json")))
+		return
+	}
+
+	c.Data(200, "application/vnd.docker.distribution.manifest.v2+json", []byte(loadFixture("mock/fixtures/manifest.json")))
+}
+
+func headBlob(c *gin.Context) {
+	// blobs for images named existing are already present
+	if strings.Contains(c.Param("image"), "existing") {
+		c.Status(200)
+		return
+	}
+
+	c.Status(404)
+}
+
+func startUpload(c *gin.Context) {
+	c.Header("Location", fmt.Sprintf("/api/docker/%s/v2/%s/blobs/uploads/%s", c.Param("registry"), c.Param("image"), "d8d9b4e6"))
+	c.Status(202)
+}
+
+func completeUpload(c *gin.Context) {
+	digest := c.Query("digest")
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(400, "Unable to read blob")
+		return
+	}
+
+	sum := sha256.Sum256(data)
+
+	// verify the uploaded blob matches the digest
+	if digest != "sha256:"+hex.EncodeToString(sum[:]) {
+		c.JSON(400, fmt.Sprintf("Digest %s does not match uploaded blob", digest))
+		return
+	}
+
+	c.Status(201)
+}
+
+func putManifest(c *gin.Context) {
+	if strings.Contains(c.Param("registry"), "not-found") {
+		c.JSON(404, fmt.Sprintf("Registry %s does not exist", c.Param("registry")))
+		return
+	}
+
+	c.Status(201)
+}
+
 func promoteImage(c *gin.Context) {
 	registry := c.Param("registry")
 
@@ -94,6 +325,21 @@ func promoteImage(c *gin.Context) {
 		return
 	}
 
+	body := make(map[string]interface{})
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		c.JSON(400, "Invalid promotion request")
+		return
+	}
+
+	image, _ := body["dockerRepository"].(string)
+
+	if strings.Contains(image, "not-found") {
+		c.JSON(404, fmt.Sprintf("Image %s does not exist", image))
+		return
+	}
+
 	c.JSON(200, "Promotion ended successfully")
 }
 
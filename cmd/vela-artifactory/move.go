@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/sirupsen/logrus"
+)
+
+const moveAction = "move"
+
+// Move represents the plugin configuration for move information.
+type Move struct {
+	Selection
+
+	// Flat is a flag that enables removing source file directory hierarchy
+	Flat bool
+	// Recursive is a flag that enables moving sub-directories from source
+	Recursive bool
+	// Path is the source path to artifact(s) to move
+	Path string
+	// Target is the path to move artifact(s) to
+	Target string
+	// Assert are the properties the artifact(s) must or must not have to be moved
+	Assert PropAssertion
+}
+
+// Exec formats and runs the commands for moving artifacts in Artifactory.
+func (m *Move) Exec(cli artifactory.ArtifactoryServicesManager) error {
+	logrus.Trace("running move with provided configuration")
+
+	start := time.Now()
+	logger := actionLogger(moveAction, m.Path).WithField("target", m.Target)
+
+	// log the artifact(s) selected for moving
+	err := m.searchSelection(cli, logger, m.CommonParams(m.Path, m.Recursive))
+	if err != nil {
+		return err
+	}
+
+	// verify the properties of the artifact(s) before moving any of them
+	if m.Assert.Enabled() {
+		err = m.Assert.Assert(cli, logger, m.CommonParams(m.Path, m.Recursive))
+		if err != nil {
+			return err
+		}
+	}
+
+	// create new move parameters
+	p := services.NewMoveCopyParams()
+
+	// add move configuration to move parameters
+	p.CommonParams = m.CommonParams(m.Path, m.Recursive)
+	p.Target = m.Target
+	p.Flat = m.Flat
+
+	// send API call to move artifacts in Artifactory
+	success, failed, err := cli.Move(p)
+	if err != nil {
+		return err
+	}
+
+	withDuration(logger, start).WithFields(logrus.Fields{
+		"success": success,
+		"failed":  failed,
+	}).Infof("Moved %d artifact(s) to %s", success, m.Target)
+
+	return nil
+}
+
+// Validate verifies the Move is properly configured.
+func (m *Move) Validate() error {
+	logrus.Trace("validating move plugin configuration")
+
+	// verify the selection is valid
+	err := m.Selection.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid move selection provided: %w", err)
+	}
+
+	// verify path or aql is provided
+	err = m.validatePath(moveAction, m.Path)
+	if err != nil {
+		return err
+	}
+
+	// verify target is provided
+	if len(m.Target) == 0 {
+		return fmt.Errorf("no move target provided")
+	}
+
+	// verify the property assertions are valid
+	err = m.Assert.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid move assertion provided: %w", err)
+	}
+
+	return nil
+}
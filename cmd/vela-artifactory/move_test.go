@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_Move_Exec(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "move",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Move: &Move{
+			Flat:      false,
+			Recursive: false,
+			Path:      "foo/bar",
+			Target:    "bar/foo",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err %v", err)
+	}
+}
+
+func TestArtifactory_Move_Exec_Error(t *testing.T) {
+	// setup types
+	config := &Config{
+		Action:   "move",
+		URL:      mock.InvalidArtifactoryServerURL,
+		Username: mock.Username,
+		Password: mock.Password,
+		Client: &Client{
+			Retries:            3,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	cli, err := config.New()
+	if err != nil {
+		t.Errorf("Unable to create Artifactory client: %v", err)
+	}
+
+	m := &Move{
+		Path:   "foo/bar",
+		Target: "bar/foo",
+	}
+
+	err = m.Exec(*cli)
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestArtifactory_Move_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		move    *Move
+		wantErr bool
+	}{
+		{
+			name: "path",
+			move: &Move{Path: "foo/bar", Target: "bar/foo"},
+		},
+		{
+			name: "aql",
+			move: &Move{Selection: Selection{Aql: `items.find({"repo":"foo"})`}, Target: "bar/foo"},
+		},
+		{
+			name:    "no path",
+			move:    &Move{Target: "bar/foo"},
+			wantErr: true,
+		},
+		{
+			name:    "no target",
+			move:    &Move{Path: "foo/bar"},
+			wantErr: true,
+		},
+		{
+			name:    "write aql",
+			move:    &Move{Selection: Selection{Aql: `items.find({"repo":"foo"}).delete()`}, Target: "bar/foo"},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.move.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,471 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// ociRefNameAnnotation is the annotation for the name of a reference in an OCI image layout.
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+	// mediaTypeDockerConfig is the media type for a Docker image configuration.
+	mediaTypeDockerConfig = "application/vnd.docker.container.image.v1+json"
+	// mediaTypeDockerLayer is the media type for an uncompressed Docker image layer.
+	mediaTypeDockerLayer = "application/vnd.docker.image.rootfs.diff.tar"
+)
+
+// digestRe matches a valid content digest.
+var digestRe = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ociDescriptor represents a content descriptor for a blob or manifest.
+//
+// https://github.com/opencontainers/image-spec/blob/main/descriptor.md
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest represents an image manifest, image index or manifest list.
+//
+// https://github.com/opencontainers/image-spec/blob/main/manifest.md
+type ociManifest struct {
+	SchemaVersion int              `json:"schemaVersion"`
+	MediaType     string           `json:"mediaType,omitempty"`
+	Config        *ociDescriptor   `json:"config,omitempty"`
+	Layers        []*ociDescriptor `json:"layers,omitempty"`
+	Manifests     []*ociDescriptor `json:"manifests,omitempty"`
+}
+
+// isIndex reports whether the media type is for an image index or manifest list.
+func isIndex(mediaType string) bool {
+	return mediaType == mediaTypeOCIImageIndex || mediaType == mediaTypeDockerManifestList
+}
+
+// dockerSaveManifest represents an image in the manifest of a docker save tarball.
+type dockerSaveManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// ociImage represents an image read from an OCI image layout or docker save tarball.
+type ociImage struct {
+	// Root is the descriptor for the manifest or index to push
+	Root *ociDescriptor
+
+	// open opens a file within the layout or tarball
+	open func(name string) (io.ReadCloser, error)
+	// files maps the digest of a blob to a file that is not stored by digest
+	files map[string]string
+	// generated holds manifests created for the image that are not stored in the layout
+	generated map[string][]byte
+}
+
+// loadOCIImage reads the image from an OCI image layout directory, an
+// OCI image layout tarball or a docker save tarball at the source.
+func loadOCIImage(source, refName string) (*ociImage, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &ociImage{
+		files:     make(map[string]string),
+		generated: make(map[string][]byte),
+	}
+
+	if info.IsDir() {
+		image.open = dirOpener(source)
+	} else {
+		image.open = tarOpener(source)
+	}
+
+	// check for an OCI image layout
+	index, err := image.readFile("index.json")
+	if err == nil {
+		return image, image.loadLayout(index, refName)
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	// check for a docker save tarball
+	manifest, err := image.readFile("manifest.json")
+	if err == nil {
+		return image, image.loadDockerSave(manifest)
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("no OCI image layout or docker save tarball found in %s", source)
+}
+
+// loadLayout selects the manifest to push from the index of an OCI image layout.
+func (i *ociImage) loadLayout(data []byte, refName string) error {
+	index := new(ociManifest)
+
+	err := json.Unmarshal(data, index)
+	if err != nil {
+		return fmt.Errorf("unable to parse OCI image layout index: %w", err)
+	}
+
+	var candidates []*ociDescriptor
+
+	for _, manifest := range index.Manifests {
+		if len(refName) == 0 || manifest.Annotations[ociRefNameAnnotation] == refName {
+			candidates = append(candidates, manifest)
+		}
+	}
+
+	switch {
+	case len(candidates) == 0 && len(refName) > 0:
+		return fmt.Errorf("no manifest found in OCI image layout for ref name %s", refName)
+	case len(candidates) == 0:
+		return fmt.Errorf("no manifest found in OCI image layout")
+	case len(candidates) > 1:
+		return fmt.Errorf("OCI image layout contains %d manifests, provide a ref name to select one", len(candidates))
+	}
+
+	i.Root = candidates[0]
+
+	// capture the media type from the manifest when the index does not provide it
+	if len(i.Root.MediaType) == 0 {
+		manifest, err := i.Manifest(i.Root.Digest)
+		if err != nil {
+			return err
+		}
+
+		i.Root.MediaType = manifest.MediaType
+		if len(i.Root.MediaType) == 0 {
+			i.Root.MediaType = mediaTypeOCIManifest
+		}
+	}
+
+	return nil
+}
+
+// loadDockerSave creates an image manifest from the manifest of a docker save tarball.
+func (i *ociImage) loadDockerSave(data []byte) error {
+	var images []*dockerSaveManifest
+
+	err := json.Unmarshal(data, &images)
+	if err != nil {
+		return fmt.Errorf("unable to parse docker save manifest: %w", err)
+	}
+
+	if len(images) != 1 {
+		return fmt.Errorf("docker save tarball contains %d images, want 1", len(images))
+	}
+
+	config, err := i.describe(images[0].Config, mediaTypeDockerConfig)
+	if err != nil {
+		return err
+	}
+
+	manifest := &ociManifest{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeDockerManifest,
+		Config:        config,
+	}
+
+	for _, layer := range images[0].Layers {
+		descriptor, err := i.describe(layer, mediaTypeDockerLayer)
+		if err != nil {
+			return err
+		}
+
+		manifest.Layers = append(manifest.Layers, descriptor)
+	}
+
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	i.Root = &ociDescriptor{
+		MediaType: mediaTypeDockerManifest,
+		Digest:    digestOf(content),
+		Size:      int64(len(content)),
+	}
+
+	i.generated[i.Root.Digest] = content
+
+	return nil
+}
+
+// describe creates a descriptor for a file that is not stored by digest.
+func (i *ociImage) describe(name, mediaType string) (*ociDescriptor, error) {
+	r, err := i.open(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s: %w", name, err)
+	}
+
+	defer r.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", name, err)
+	}
+
+	descriptor := &ociDescriptor{
+		MediaType: mediaType,
+		Digest:    "sha256:" + hex.EncodeToString(hash.Sum(nil)),
+		Size:      size,
+	}
+
+	i.files[descriptor.Digest] = name
+
+	return descriptor, nil
+}
+
+// Blob opens the content for the digest.
+func (i *ociImage) Blob(digest string) (io.ReadCloser, error) {
+	if content, ok := i.generated[digest]; ok {
+		return io.NopCloser(strings.NewReader(string(content))), nil
+	}
+
+	if name, ok := i.files[digest]; ok {
+		return i.open(name)
+	}
+
+	// verify the digest before using it as a path within the layout
+	if !digestRe.MatchString(digest) {
+		return nil, fmt.Errorf("unsupported digest %s", digest)
+	}
+
+	algorithm, encoded, _ := strings.Cut(digest, ":")
+
+	return i.open(path.Join("blobs", algorithm, encoded))
+}
+
+// Content reads the content for the digest.
+func (i *ociImage) Content(digest string) ([]byte, error) {
+	r, err := i.Blob(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// Manifest reads and parses the manifest for the digest.
+func (i *ociImage) Manifest(digest string) (*ociManifest, error) {
+	content, err := i.Content(digest)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read manifest %s: %w", digest, err)
+	}
+
+	manifest := new(ociManifest)
+
+	err = json.Unmarshal(content, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse manifest %s: %w", digest, err)
+	}
+
+	return manifest, nil
+}
+
+// readFile reads a file within the layout or tarball.
+func (i *ociImage) readFile(name string) ([]byte, error) {
+	r, err := i.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// digestOf returns the sha256 digest for the content.
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// dirOpener returns a function that opens files within the directory.
+func dirOpener(dir string) func(name string) (io.ReadCloser, error) {
+	return func(name string) (io.ReadCloser, error) {
+		name = filepath.FromSlash(name)
+
+		// avoid reading files outside of the directory
+		if !filepath.IsLocal(name) {
+			return nil, fmt.Errorf("invalid path %s within %s", name, dir)
+		}
+
+		return os.Open(filepath.Join(dir, name))
+	}
+}
+
+// tarReadCloser reads a file within a tarball and closes the tarball.
+type tarReadCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+// Close closes the tarball.
+func (t *tarReadCloser) Close() error {
+	return t.closer.Close()
+}
+
+// tarEntry represents the location of a file within a tarball.
+type tarEntry struct {
+	// offset is the position of the content within the uncompressed tarball
+	offset int64
+	// size is the length of the content
+	size int64
+}
+
+// countingReader tracks the number of bytes read from the reader.
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+// Read reads from the reader and counts the bytes read.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+// openTar opens the tarball and returns a reader for the
+// uncompressed content, along with whether it is gzip compressed.
+func openTar(f *os.File) (io.Reader, bool, error) {
+	r := bufio.NewReader(f)
+
+	// check for the gzip magic number
+	magic, err := r.Peek(2)
+	if err != nil || magic[0] != 0x1f || magic[1] != 0x8b {
+		return r, false, nil
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return gz, true, nil
+}
+
+// indexTar scans the tarball once and returns the location of every file within it.
+func indexTar(file string) (map[string]*tarEntry, bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer f.Close()
+
+	reader, compressed, err := openTar(f)
+	if err != nil {
+		return nil, false, err
+	}
+
+	counter := &countingReader{Reader: reader}
+	tr := tar.NewReader(counter)
+
+	index := make(map[string]*tarEntry)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return index, compressed, nil
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		name := path.Clean(header.Name)
+
+		// the first file with the name is used, matching a sequential scan
+		if _, ok := index[name]; ok {
+			continue
+		}
+
+		// the content of the file starts after its header
+		index[name] = &tarEntry{offset: counter.n, size: header.Size}
+	}
+}
+
+// tarOpener returns a function that opens files within the tarball, which may be gzip compressed.
+//
+// The tarball is indexed on the first open. Files within an uncompressed tarball are read directly
+// from their offset, while a compressed tarball is decompressed up to the offset of the file.
+func tarOpener(file string) func(name string) (io.ReadCloser, error) {
+	var (
+		once       sync.Once
+		index      map[string]*tarEntry
+		compressed bool
+		indexErr   error
+	)
+
+	return func(name string) (io.ReadCloser, error) {
+		once.Do(func() {
+			index, compressed, indexErr = indexTar(file)
+		})
+
+		if indexErr != nil {
+			return nil, indexErr
+		}
+
+		entry, ok := index[path.Clean(name)]
+		if !ok {
+			return nil, fmt.Errorf("%s not found in %s: %w", name, file, fs.ErrNotExist)
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if !compressed {
+			return &tarReadCloser{Reader: io.NewSectionReader(f, entry.offset, entry.size), closer: f}, nil
+		}
+
+		reader, _, err := openTar(f)
+		if err != nil {
+			f.Close()
+
+			return nil, err
+		}
+
+		// skip the content before the file within the decompressed tarball
+		_, err = io.CopyN(io.Discard, reader, entry.offset)
+		if err != nil {
+			f.Close()
+
+			return nil, err
+		}
+
+		return &tarReadCloser{Reader: io.LimitReader(reader, entry.size), closer: f}, nil
+	}
+}
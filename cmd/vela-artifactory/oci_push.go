@@ -0,0 +1,504 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	json "github.com/ghodss/yaml"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/sirupsen/logrus"
+)
+
+const ociPushAction = "oci-push"
+
+// OCIPush represents the plugin configuration for pushing an image from an OCI image layout.
+type OCIPush struct {
+	// Source is the path to an OCI image layout directory or tarball, or a docker save tarball
+	Source string
+	// RefName is the name of the reference to push when the OCI image layout contains multiple manifests
+	RefName string
+	// TargetRepo is the Docker repository in Artifactory to push the image to
+	TargetRepo string
+	// Image is the path to the image in the Docker registry
+	Image string
+	// Tags are the tags to assign to the image after pushing (supports templates)
+	Tags []string
+	// Props are properties to set on the image after pushing (supports templates)
+	Props []*Prop
+	// RawProps is raw input of properties provided for plugin
+	RawProps string
+	// DryRun is a flag to set to log the blobs, manifests and properties without pushing them
+	DryRun bool
+}
+
+// pushTemplateData represents the values available to push property templates.
+type pushTemplateData struct {
+	*BuildMetadata
+
+	// TargetRepo is the repository the image was pushed to
+	TargetRepo string
+	// TargetImage is the image that was pushed
+	TargetImage string
+	// TargetTag is the tag the image was pushed to
+	TargetTag string
+	// Digest is the digest of the manifest that was pushed
+	Digest string
+}
+
+// ociRegistry pushes content to a Docker repository through the registry API in Artifactory.
+type ociRegistry struct {
+	cli    artifactory.ArtifactoryServicesManager
+	logger *logrus.Entry
+	repo   string
+	image  string
+	dryRun bool
+
+	// pushed is the number of blobs uploaded
+	pushed int
+	// existing is the number of blobs already present in the repository
+	existing int
+}
+
+// Exec formats and runs the commands for pushing an image in Artifactory.
+func (o *OCIPush) Exec(cli artifactory.ArtifactoryServicesManager) error {
+	logrus.Trace("running oci-push with provided configuration")
+
+	start := time.Now()
+	logger := actionLogger(ociPushAction, fmt.Sprintf("%s/%s", o.TargetRepo, o.Image))
+	build := newBuildMetadata()
+
+	// read the image from the workspace
+	image, err := loadOCIImage(o.Source, o.RefName)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Pushing %s %s from %s", image.Root.MediaType, image.Root.Digest, o.Source)
+
+	// resolve the tags from the templates
+	tags, err := o.ResolveTags(build)
+	if err != nil {
+		return err
+	}
+
+	registry := &ociRegistry{cli: cli, logger: logger, repo: o.TargetRepo, image: o.Image, dryRun: o.DryRun}
+
+	var platforms []string
+
+	if isIndex(image.Root.MediaType) {
+		index, err := image.Manifest(image.Root.Digest)
+		if err != nil {
+			return err
+		}
+
+		// push every platform manifest referenced by the index
+		for _, descriptor := range index.Manifests {
+			err = registry.pushImage(image, descriptor, descriptor.Digest)
+			if err != nil {
+				return err
+			}
+
+			platforms = append(platforms, descriptor.Digest)
+		}
+	} else {
+		err = registry.pushBlobs(image, image.Root)
+		if err != nil {
+			return err
+		}
+	}
+
+	content, err := image.Content(image.Root.Digest)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		// send API call to push the manifest for the tag
+		err = registry.pushManifest(tag, image.Root.MediaType, content)
+		if err != nil {
+			return err
+		}
+
+		if !o.DryRun {
+			logger.WithField("artifact", fmt.Sprintf("%s:%s", o.Image, tag)).Infof("Pushed %s:%s", o.Image, tag)
+		}
+	}
+
+	// set the properties on the pushed image
+	if len(o.Props) > 0 {
+		err = o.setProperties(cli, logger, build, image.Root.Digest, tags, platforms)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.DryRun {
+		withDuration(logger, start).Infof("Dry run enabled, skipping push of %d blob(s) and %d tag(s)", registry.pushed, len(tags))
+
+		return nil
+	}
+
+	withDuration(logger, start).WithFields(logrus.Fields{
+		"success":  registry.pushed,
+		"existing": registry.existing,
+	}).Infof("Pushed %d blob(s) and skipped %d existing blob(s) for %d tag(s)", registry.pushed, registry.existing, len(tags))
+
+	return nil
+}
+
+// ResolveTags renders the tag templates using the build information.
+func (o *OCIPush) ResolveTags(build *BuildMetadata) ([]string, error) {
+	var tags []string
+
+	for _, tag := range o.Tags {
+		rendered, err := renderTemplate("tags", tag, build)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render tag %s: %w", tag, err)
+		}
+
+		rendered = strings.TrimSpace(rendered)
+
+		// remove empty and duplicate tags while preserving order
+		if len(rendered) == 0 || slices.Contains(tags, rendered) {
+			continue
+		}
+
+		if !dockerTagRe.MatchString(rendered) {
+			return nil, fmt.Errorf("invalid tag %q resolved", rendered)
+		}
+
+		tags = append(tags, rendered)
+	}
+
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no oci-push tags resolved")
+	}
+
+	return tags, nil
+}
+
+// setProperties assigns the properties to every tag and platform manifest of the pushed image.
+func (o *OCIPush) setProperties(
+	cli artifactory.ArtifactoryServicesManager,
+	logger *logrus.Entry,
+	build *BuildMetadata,
+	digest string,
+	tags, platforms []string,
+) error {
+	var total int
+
+	for _, tag := range tags {
+		data := &pushTemplateData{
+			BuildMetadata: build,
+			TargetRepo:    o.TargetRepo,
+			TargetImage:   o.Image,
+			TargetTag:     tag,
+			Digest:        digest,
+		}
+
+		rendered, err := renderProps(o.Props, data)
+		if err != nil {
+			return err
+		}
+
+		props := strings.Join(rendered, ";")
+
+		parsed, err := utils.ParseProperties(props)
+		if err != nil {
+			return fmt.Errorf("unable to parse oci-push properties: %w", err)
+		}
+
+		folders := []string{fmt.Sprintf("%s/%s/%s", o.TargetRepo, o.Image, tag)}
+
+		// platform manifests are stored in a folder named after their digest
+		for _, platform := range platforms {
+			folders = append(folders, fmt.Sprintf("%s/%s/%s", o.TargetRepo, o.Image, platform))
+		}
+
+		for _, folder := range folders {
+			// the properties are set through raw API calls the client dry run does not cover
+			if o.DryRun {
+				logger.WithField("artifact", folder).Infof("  [dry run] props [%s] -> %s", props, folder)
+
+				continue
+			}
+
+			err := setFolderProperties(cli, folder, parsed.ToEncodedString(true))
+			if err != nil {
+				return err
+			}
+
+			logrus.Tracef("assigned properties [%s] to %s and the items within it", props, folder)
+
+			total++
+		}
+	}
+
+	if o.DryRun {
+		return nil
+	}
+
+	logger.WithField("success", total).Infof("Successfully assigned properties to %d image folder(s).", total)
+
+	return nil
+}
+
+// Unmarshal captures the provided properties and
+// serializes them into their expected form.
+func (o *OCIPush) Unmarshal() error {
+	logrus.Trace("unmarshaling raw props")
+
+	if len(o.RawProps) == 0 {
+		return nil
+	}
+
+	// serialize raw properties into expected Props type
+	return json.Unmarshal([]byte(o.RawProps), &o.Props)
+}
+
+// Validate verifies the OCIPush is properly configured.
+func (o *OCIPush) Validate() error {
+	logrus.Trace("validating oci-push plugin configuration")
+
+	// verify a source is provided
+	if len(o.Source) == 0 {
+		return fmt.Errorf("no oci-push source provided")
+	}
+
+	// verify the source exists in the workspace
+	_, err := os.Stat(o.Source)
+	if err != nil {
+		return fmt.Errorf("invalid oci-push source provided: %w", err)
+	}
+
+	// verify a target repo is provided
+	if len(o.TargetRepo) == 0 {
+		return fmt.Errorf("no oci-push target repository provided")
+	}
+
+	// verify an image is provided
+	if len(o.Image) == 0 {
+		return fmt.Errorf("no oci-push image provided")
+	}
+
+	// verify tags are provided
+	if len(o.Tags) == 0 {
+		return fmt.Errorf("no oci-push tags provided")
+	}
+
+	// verify the tags are valid templates
+	for _, tag := range o.Tags {
+		_, err := parseTemplate("tags", tag)
+		if err != nil {
+			return fmt.Errorf("invalid oci-push tag %s provided: %w", tag, err)
+		}
+	}
+
+	// serialize provided properties into expected type
+	err = o.Unmarshal()
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal oci-push props: %w", err)
+	}
+
+	// verify the properties are valid
+	err = validatePropTemplates(o.Props)
+	if err != nil {
+		return fmt.Errorf("invalid oci-push prop provided: %w", err)
+	}
+
+	err = validateSetMode(o.Props)
+	if err != nil {
+		return fmt.Errorf("invalid oci-push prop provided: %w", err)
+	}
+
+	return nil
+}
+
+// url returns the URL for the path of the image in the registry API.
+func (r *ociRegistry) url(path string) string {
+	return fmt.Sprintf("%sapi/docker/%s/v2/%s/%s", r.cli.GetConfig().GetServiceDetails().GetUrl(), r.repo, r.image, path)
+}
+
+// details returns the HTTP details using the credentials configured for the client.
+func (r *ociRegistry) details(headers map[string]string) *httputils.HttpClientDetails {
+	details := r.cli.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+
+	if details.Headers == nil {
+		details.Headers = make(map[string]string)
+	}
+
+	for key, value := range headers {
+		details.Headers[key] = value
+	}
+
+	return &details
+}
+
+// pushImage pushes the blobs and manifest for an image manifest by reference.
+func (r *ociRegistry) pushImage(image *ociImage, descriptor *ociDescriptor, reference string) error {
+	err := r.pushBlobs(image, descriptor)
+	if err != nil {
+		return err
+	}
+
+	content, err := image.Content(descriptor.Digest)
+	if err != nil {
+		return err
+	}
+
+	mediaType := descriptor.MediaType
+	if len(mediaType) == 0 {
+		mediaType = mediaTypeOCIManifest
+	}
+
+	return r.pushManifest(reference, mediaType, content)
+}
+
+// pushBlobs pushes the config and layers for an image manifest.
+func (r *ociRegistry) pushBlobs(image *ociImage, descriptor *ociDescriptor) error {
+	manifest, err := image.Manifest(descriptor.Digest)
+	if err != nil {
+		return err
+	}
+
+	if manifest.Config == nil {
+		return fmt.Errorf("manifest %s does not contain a config", descriptor.Digest)
+	}
+
+	for _, blob := range append([]*ociDescriptor{manifest.Config}, manifest.Layers...) {
+		err = r.pushBlob(image, blob)
+		if err != nil {
+			return fmt.Errorf("unable to push blob %s: %w", blob.Digest, err)
+		}
+	}
+
+	return nil
+}
+
+// pushBlob uploads the blob unless it is already present in the repository.
+//
+// https://distribution.github.io/distribution/spec/api/#pushing-an-image
+func (r *ociRegistry) pushBlob(image *ociImage, blob *ociDescriptor) error {
+	// send API call to check if the blob exists
+	resp, _, err := r.cli.Client().SendHead(r.url("blobs/"+blob.Digest), r.details(nil))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		logrus.Tracef("blob %s already exists in %s", blob.Digest, r.repo)
+
+		r.existing++
+
+		return nil
+	}
+
+	if r.dryRun {
+		r.logger.WithField("artifact", blob.Digest).Infof("  [dry run] blob %s (%d bytes)", blob.Digest, blob.Size)
+
+		r.pushed++
+
+		return nil
+	}
+
+	// send API call to start an upload for the blob
+	resp, body, err := r.cli.Client().SendPost(r.url("blobs/uploads/"), nil, r.details(nil))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unable to start upload: %s %s", resp.Status, string(body))
+	}
+
+	location, err := r.location(resp.Header.Get("Location"), blob.Digest)
+	if err != nil {
+		return err
+	}
+
+	content, err := image.Blob(blob.Digest)
+	if err != nil {
+		return err
+	}
+
+	defer content.Close()
+
+	logrus.Tracef("uploading blob %s (%d bytes) to %s", blob.Digest, blob.Size, r.repo)
+
+	// send API call to upload the blob in a single request
+	_, _, err = r.cli.Client().UploadFileFromReader(
+		content,
+		location,
+		r.details(map[string]string{"Content-Type": "application/octet-stream"}),
+		blob.Size,
+	)
+	if err != nil {
+		return err
+	}
+
+	r.pushed++
+
+	return nil
+}
+
+// location returns the URL for completing an upload with the digest.
+func (r *ociRegistry) location(header, digest string) (string, error) {
+	if len(header) == 0 {
+		return "", fmt.Errorf("no upload location returned")
+	}
+
+	base, err := url.Parse(r.cli.GetConfig().GetServiceDetails().GetUrl())
+	if err != nil {
+		return "", err
+	}
+
+	location, err := url.Parse(header)
+	if err != nil {
+		return "", fmt.Errorf("invalid upload location %s returned: %w", header, err)
+	}
+
+	// resolve relative locations against the Artifactory instance
+	location = base.ResolveReference(location)
+
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	return location.String(), nil
+}
+
+// pushManifest uploads the manifest by reference, which is either a tag or digest.
+func (r *ociRegistry) pushManifest(reference, mediaType string, content []byte) error {
+	if r.dryRun {
+		r.logger.WithField("artifact", fmt.Sprintf("%s:%s", r.image, reference)).Infof("  [dry run] manifest %s:%s", r.image, reference)
+
+		return nil
+	}
+
+	logrus.Tracef("pushing manifest %s for %s to %s", reference, r.image, r.repo)
+
+	// send API call to upload the manifest
+	resp, body, err := r.cli.Client().SendPut(
+		r.url("manifests/"+reference),
+		content,
+		r.details(map[string]string{"Content-Type": mediaType}),
+	)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to push manifest %s: %s %s", reference, resp.Status, string(body))
+	}
+
+	return nil
+}
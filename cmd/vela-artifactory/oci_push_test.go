@@ -0,0 +1,574 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+// writeBlob writes the content to the blobs of the OCI image layout and returns its descriptor.
+func writeBlob(t *testing.T, dir, mediaType string, content []byte) *ociDescriptor {
+	t.Helper()
+
+	digest := digestOf(content)
+
+	blobs := filepath.Join(dir, "blobs", "sha256")
+
+	err := os.MkdirAll(blobs, 0o755)
+	if err != nil {
+		t.Fatalf("unable to create blobs: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(blobs, strings.TrimPrefix(digest, "sha256:")), content, 0o600)
+	if err != nil {
+		t.Fatalf("unable to write blob: %v", err)
+	}
+
+	return &ociDescriptor{MediaType: mediaType, Digest: digest, Size: int64(len(content))}
+}
+
+// writeManifest writes an image manifest with a config and layer to the OCI image layout.
+func writeManifest(t *testing.T, dir, arch string) *ociDescriptor {
+	t.Helper()
+
+	manifest := &ociManifest{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIManifest,
+		Config:        writeBlob(t, dir, "application/vnd.oci.image.config.v1+json", []byte(`{"architecture":"`+arch+`"}`)),
+		Layers: []*ociDescriptor{
+			writeBlob(t, dir, "application/vnd.oci.image.layer.v1.tar", []byte("layer for "+arch)),
+		},
+	}
+
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("unable to marshal manifest: %v", err)
+	}
+
+	return writeBlob(t, dir, mediaTypeOCIManifest, content)
+}
+
+// writeLayout writes the index for an OCI image layout with the manifests.
+func writeLayout(t *testing.T, dir string, manifests ...*ociDescriptor) {
+	t.Helper()
+
+	index, err := json.Marshal(&ociManifest{SchemaVersion: 2, Manifests: manifests})
+	if err != nil {
+		t.Fatalf("unable to marshal index: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "index.json"), index, 0o600)
+	if err != nil {
+		t.Fatalf("unable to write index: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write oci-layout: %v", err)
+	}
+}
+
+// writeTar writes every file in the directory to a gzip compressed tarball.
+func writeTar(t *testing.T, dir, file string) {
+	t.Helper()
+
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatalf("unable to create tarball: %v", err)
+	}
+
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = tw.AddFS(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("unable to write tarball: %v", err)
+	}
+
+	tw.Close()
+	gz.Close()
+}
+
+// recordingServer returns a mock server that records the requests sent to the registry API.
+func recordingServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			mu.Lock()
+			requests = append(requests, r.URL.Path)
+			mu.Unlock()
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests
+	}
+}
+
+// ociPlugin returns a plugin configured to push the source to the server.
+func ociPlugin(url, source, image string) *Plugin {
+	return &Plugin{
+		Config: &Config{
+			Action:   "oci-push",
+			URL:      url,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		OCIPush: &OCIPush{
+			Source:     source,
+			TargetRepo: "docker",
+			Image:      image,
+			Tags:       []string{"latest", "{{ .BuildNumber }}"},
+		},
+	}
+}
+
+// countPrefix returns the number of values with the prefix.
+func countPrefix(values []string, prefix string) int {
+	var count int
+
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestArtifactory_OCIPush_Exec_Layout(t *testing.T) {
+	// setup types
+	t.Setenv("VELA_BUILD_NUMBER", "42")
+
+	s, requests := recordingServer(t)
+
+	dir := t.TempDir()
+	writeLayout(t, dir, writeManifest(t, dir, "amd64"))
+
+	p := ociPlugin(s.URL, dir, "hello-world")
+	p.OCIPush.RawProps = `[{"name": "vela.build", "value": "{{ .BuildNumber }}"}]`
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	got := requests()
+
+	if countPrefix(got, "/api/docker/docker/v2/hello-world/blobs/uploads/") != 2 {
+		t.Errorf("Exec uploaded blobs %v, want 2 blobs", got)
+	}
+
+	for _, want := range []string{
+		"/api/docker/docker/v2/hello-world/manifests/latest",
+		"/api/docker/docker/v2/hello-world/manifests/42",
+		"/api/storage/docker/hello-world/latest",
+		"/api/storage/docker/hello-world/42",
+	} {
+		if countPrefix(got, want) != 1 {
+			t.Errorf("Exec requests are %v, want %s", got, want)
+		}
+	}
+}
+
+func TestArtifactory_OCIPush_Exec_Index(t *testing.T) {
+	// setup types
+	s, requests := recordingServer(t)
+
+	dir := t.TempDir()
+
+	amd64 := writeManifest(t, dir, "amd64")
+	arm64 := writeManifest(t, dir, "arm64")
+
+	index, err := json.Marshal(&ociManifest{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIImageIndex,
+		Manifests:     []*ociDescriptor{amd64, arm64},
+	})
+	if err != nil {
+		t.Fatalf("unable to marshal index: %v", err)
+	}
+
+	writeLayout(t, dir, writeBlob(t, dir, mediaTypeOCIImageIndex, index))
+
+	file := filepath.Join(t.TempDir(), "image.tar.gz")
+	writeTar(t, dir, file)
+
+	p := ociPlugin(s.URL, file, "multi-arch")
+	p.OCIPush.Tags = []string{"1.0.0"}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	got := requests()
+
+	for _, want := range []string{
+		"/api/docker/docker/v2/multi-arch/manifests/" + amd64.Digest,
+		"/api/docker/docker/v2/multi-arch/manifests/" + arm64.Digest,
+		"/api/docker/docker/v2/multi-arch/manifests/1.0.0",
+	} {
+		if countPrefix(got, want) != 1 {
+			t.Errorf("Exec requests are %v, want %s", got, want)
+		}
+	}
+
+	if countPrefix(got, "/api/docker/docker/v2/multi-arch/blobs/uploads/") != 4 {
+		t.Errorf("Exec uploaded blobs %v, want 4 blobs", got)
+	}
+}
+
+func TestArtifactory_OCIPush_Exec_DockerSave(t *testing.T) {
+	// setup types
+	s, requests := recordingServer(t)
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"manifest.json":   `[{"Config": "config.json", "RepoTags": ["hello-world:latest"], "Layers": ["layer/layer.tar"]}]`,
+		"config.json":     `{"architecture": "amd64"}`,
+		"layer/layer.tar": "layer",
+	}
+
+	for name, content := range files {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755)
+		if err != nil {
+			t.Fatalf("unable to create directory: %v", err)
+		}
+
+		err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("unable to write file: %v", err)
+		}
+	}
+
+	file := filepath.Join(t.TempDir(), "image.tar")
+	writeTar(t, dir, file)
+
+	p := ociPlugin(s.URL, file, "hello-world")
+	p.OCIPush.Tags = []string{"latest"}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	got := requests()
+
+	if countPrefix(got, "/api/docker/docker/v2/hello-world/blobs/uploads/") != 2 {
+		t.Errorf("Exec uploaded blobs %v, want 2 blobs", got)
+	}
+
+	if countPrefix(got, "/api/docker/docker/v2/hello-world/manifests/latest") != 1 {
+		t.Errorf("Exec did not push manifest: %v", got)
+	}
+}
+
+func TestArtifactory_OCIPush_Exec_ExistingBlobs(t *testing.T) {
+	// setup types
+	s, requests := recordingServer(t)
+
+	dir := t.TempDir()
+	writeLayout(t, dir, writeManifest(t, dir, "amd64"))
+
+	p := ociPlugin(s.URL, dir, "existing")
+	p.OCIPush.Tags = []string{"latest"}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	if countPrefix(requests(), "/api/docker/docker/v2/existing/blobs/uploads/") != 0 {
+		t.Errorf("Exec uploaded blobs that already exist")
+	}
+}
+
+func TestArtifactory_OCIPush_Exec_DryRun(t *testing.T) {
+	// setup types
+	s, requests := requestServer(t)
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	dir := t.TempDir()
+	writeLayout(t, dir, writeManifest(t, dir, "amd64"))
+
+	p := ociPlugin(s.URL, dir, "hello-world")
+	p.Config.DryRun = true
+	p.OCIPush.Tags = []string{"latest"}
+	p.OCIPush.DryRun = true
+	p.OCIPush.RawProps = `[{"name": "os", "value": "linux"}]`
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	// only the existence of the blobs is checked
+	for _, request := range requests() {
+		if !strings.HasPrefix(request, http.MethodHead+" ") {
+			t.Errorf("Exec sent %s in dry run", request)
+		}
+	}
+
+	var blobs, manifests, props int
+
+	for _, entry := range hook.AllEntries() {
+		switch {
+		case strings.HasPrefix(entry.Message, "  [dry run] blob "):
+			blobs++
+		case entry.Message == "  [dry run] manifest hello-world:latest":
+			manifests++
+		case entry.Message == "  [dry run] props [os=linux] -> docker/hello-world/latest":
+			props++
+		}
+	}
+
+	if blobs != 2 || manifests != 1 || props != 1 {
+		t.Errorf("Exec logged %d blob(s), %d manifest(s) and %d props, want 2, 1 and 1", blobs, manifests, props)
+	}
+}
+
+func TestArtifactory_tarOpener(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"index.json":       `{"schemaVersion": 2}`,
+		"blobs/sha256/abc": "first blob",
+		"blobs/sha256/def": strings.Repeat("second blob", 100),
+	}
+
+	plain := filepath.Join(dir, "image.tar")
+
+	f, err := os.Create(plain)
+	if err != nil {
+		t.Fatalf("unable to create tarball: %v", err)
+	}
+
+	tw := tar.NewWriter(f)
+
+	for _, name := range []string{"index.json", "blobs/sha256/abc", "blobs/sha256/def"} {
+		err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(files[name]))})
+		if err != nil {
+			t.Fatalf("unable to write header: %v", err)
+		}
+
+		_, err = tw.Write([]byte(files[name]))
+		if err != nil {
+			t.Fatalf("unable to write file: %v", err)
+		}
+	}
+
+	tw.Close()
+	f.Close()
+
+	layout := filepath.Join(dir, "layout")
+
+	for name, content := range files {
+		err = os.MkdirAll(filepath.Dir(filepath.Join(layout, name)), 0o755)
+		if err != nil {
+			t.Fatalf("unable to create directory: %v", err)
+		}
+
+		err = os.WriteFile(filepath.Join(layout, name), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("unable to write file: %v", err)
+		}
+	}
+
+	compressed := filepath.Join(dir, "image.tar.gz")
+	writeTar(t, layout, compressed)
+
+	// run tests
+	for _, file := range []string{plain, compressed} {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			open := tarOpener(file)
+
+			// open the files out of order to read them from the index
+			for _, name := range []string{"blobs/sha256/def", "index.json", "./blobs/sha256/abc"} {
+				r, err := open(name)
+				if err != nil {
+					t.Fatalf("open %s returned err: %v", name, err)
+				}
+
+				got, err := io.ReadAll(r)
+				r.Close()
+
+				if err != nil {
+					t.Fatalf("unable to read %s: %v", name, err)
+				}
+
+				if string(got) != files[path.Clean(name)] {
+					t.Errorf("open %s is %q, want %q", name, got, files[path.Clean(name)])
+				}
+			}
+
+			_, err := open("blobs/sha256/missing")
+			if !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("open returned err %v, want %v", err, fs.ErrNotExist)
+			}
+		})
+	}
+}
+
+func TestArtifactory_OCIPush_Exec_Error(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	dir := t.TempDir()
+	writeLayout(t, dir, writeManifest(t, dir, "amd64"))
+
+	p := ociPlugin(s.URL, dir, "hello-world")
+	p.OCIPush.TargetRepo = "not-found"
+
+	err := p.Exec()
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestArtifactory_loadOCIImage_RefName(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	amd64 := writeManifest(t, dir, "amd64")
+	amd64.Annotations = map[string]string{ociRefNameAnnotation: "amd64"}
+
+	arm64 := writeManifest(t, dir, "arm64")
+	arm64.Annotations = map[string]string{ociRefNameAnnotation: "arm64"}
+
+	writeLayout(t, dir, amd64, arm64)
+
+	_, err := loadOCIImage(dir, "")
+	if err == nil {
+		t.Errorf("loadOCIImage should have returned err")
+	}
+
+	image, err := loadOCIImage(dir, "arm64")
+	if err != nil {
+		t.Errorf("loadOCIImage returned err: %v", err)
+	}
+
+	if image != nil && image.Root.Digest != arm64.Digest {
+		t.Errorf("loadOCIImage root is %s, want %s", image.Root.Digest, arm64.Digest)
+	}
+}
+
+func TestArtifactory_OCIPush_Validate(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	// setup tests
+	tests := []struct {
+		name    string
+		o       *OCIPush
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			o:    &OCIPush{Source: dir, TargetRepo: "docker", Image: "hello-world", Tags: []string{"latest"}},
+		},
+		{
+			name:    "no source",
+			o:       &OCIPush{TargetRepo: "docker", Image: "hello-world", Tags: []string{"latest"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing source",
+			o:       &OCIPush{Source: filepath.Join(dir, "missing"), TargetRepo: "docker", Image: "hello-world", Tags: []string{"latest"}},
+			wantErr: true,
+		},
+		{
+			name:    "no target repo",
+			o:       &OCIPush{Source: dir, Image: "hello-world", Tags: []string{"latest"}},
+			wantErr: true,
+		},
+		{
+			name:    "no image",
+			o:       &OCIPush{Source: dir, TargetRepo: "docker", Tags: []string{"latest"}},
+			wantErr: true,
+		},
+		{
+			name:    "no tags",
+			o:       &OCIPush{Source: dir, TargetRepo: "docker", Image: "hello-world"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid tag template",
+			o:       &OCIPush{Source: dir, TargetRepo: "docker", Image: "hello-world", Tags: []string{"{{ .Tag"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid props",
+			o:       &OCIPush{Source: dir, TargetRepo: "docker", Image: "hello-world", Tags: []string{"latest"}, RawProps: `[{"name": "foo"}]`},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.o.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
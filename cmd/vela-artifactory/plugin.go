@@ -17,14 +17,30 @@ var (
 
 // Plugin represents the configuration loaded for the plugin.
 type Plugin struct {
+	// AssertProps arguments loaded for the plugin
+	AssertProps *AssertProps
 	// Config stores arguments loaded for the plugin
 	Config *Config
 	// Copy arguments loaded for the plugin
 	Copy *Copy
 	// Delete arguments loaded for the plugin
 	Delete *Delete
+	// DockerCleanup arguments loaded for the plugin
+	DockerCleanup *DockerCleanup
 	// DockerPromote arguments loaded for the plugin
 	DockerPromote *DockerPromote
+	// GetProp arguments loaded for the plugin
+	GetProp *GetProp
+	// HelmPublish arguments loaded for the plugin
+	HelmPublish *HelmPublish
+	// MavenDeploy arguments loaded for the plugin
+	MavenDeploy *MavenDeploy
+	// Move arguments loaded for the plugin
+	Move *Move
+	// OCIPush arguments loaded for the plugin
+	OCIPush *OCIPush
+	// Search arguments loaded for the plugin
+	Search *Search
 	// SetProp arguments loaded for the plugin
 	SetProp *SetProp
 	// Upload arguments loaded for the plugin
@@ -35,23 +51,62 @@ type Plugin struct {
 func (p *Plugin) Exec() error {
 	logrus.Debug("running plugin with provided configuration")
 
+	// write the HTTP trace, when enabled, once the action completes
+	defer p.Config.WriteTrace()
+
 	// create new Artifactory client from config configuration
 	cli, err := p.Config.New()
 	if err != nil {
 		return err
 	}
 
+	// check if pre-flight verification is enabled
+	if p.Config.Preflight || p.Config.Action == pingAction {
+		// verify connectivity, credentials and permissions for the action
+		err = p.Preflight(*cli)
+		if err != nil {
+			return err
+		}
+	}
+
 	// execute action specific configuration
 	switch p.Config.Action {
+	case assertPropsAction:
+		// execute assert-props action
+		return p.AssertProps.Exec(*cli)
 	case copyAction:
 		// execute copy action
 		return p.Copy.Exec(*cli)
 	case deleteAction:
 		// execute delete action
 		return p.Delete.Exec(*cli)
+	case dockerCleanupAction:
+		// execute docker-cleanup action
+		return p.DockerCleanup.Exec(*cli)
 	case dockerPromoteAction:
 		// execute docker-promote action
 		return p.DockerPromote.Exec(*cli)
+	case getPropAction:
+		// execute get-prop action
+		return p.GetProp.Exec(*cli)
+	case helmPublishAction:
+		// execute helm-publish action
+		return p.HelmPublish.Exec(*cli)
+	case mavenDeployAction:
+		// execute maven-deploy action
+		return p.MavenDeploy.Exec(*cli)
+	case moveAction:
+		// execute move action
+		return p.Move.Exec(*cli)
+	case ociPushAction:
+		// execute oci-push action
+		return p.OCIPush.Exec(*cli)
+	case pingAction:
+		// ping action is complete after pre-flight checks
+		return nil
+	case searchAction:
+		// execute search action
+		return p.Search.Exec(*cli)
 	case setPropAction:
 		// execute set-prop action
 		return p.SetProp.Exec(*cli)
@@ -60,12 +115,21 @@ func (p *Plugin) Exec() error {
 		return p.Upload.Exec(*cli)
 	default:
 		return fmt.Errorf(
-			"%w: %s (Valid actions: %s, %s, %s, %s, %s)",
+			"%w: %s (Valid actions: %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)",
 			ErrInvalidAction,
 			p.Config.Action,
+			assertPropsAction,
 			copyAction,
 			deleteAction,
+			dockerCleanupAction,
 			dockerPromoteAction,
+			getPropAction,
+			helmPublishAction,
+			mavenDeployAction,
+			moveAction,
+			ociPushAction,
+			pingAction,
+			searchAction,
 			setPropAction,
 			uploadAction,
 		)
@@ -84,15 +148,42 @@ func (p *Plugin) Validate() error {
 
 	// validate action specific configuration
 	switch p.Config.Action {
+	case assertPropsAction:
+		// validate assert-props configuration
+		return p.AssertProps.Validate()
 	case copyAction:
 		// validate copy configuration
 		return p.Copy.Validate()
 	case deleteAction:
 		// validate delete configuration
 		return p.Delete.Validate()
+	case dockerCleanupAction:
+		// validate docker-cleanup configuration
+		return p.DockerCleanup.Validate()
 	case dockerPromoteAction:
 		// validate docker-promote configuration
 		return p.DockerPromote.Validate()
+	case getPropAction:
+		// validate get-prop configuration
+		return p.GetProp.Validate()
+	case helmPublishAction:
+		// validate helm-publish configuration
+		return p.HelmPublish.Validate()
+	case mavenDeployAction:
+		// validate maven-deploy configuration
+		return p.MavenDeploy.Validate()
+	case moveAction:
+		// validate move configuration
+		return p.Move.Validate()
+	case ociPushAction:
+		// validate oci-push configuration
+		return p.OCIPush.Validate()
+	case pingAction:
+		// ping action has no specific configuration
+		return nil
+	case searchAction:
+		// validate search configuration
+		return p.Search.Validate()
 	case setPropAction:
 		// validate set-prop configuration
 		return p.SetProp.Validate()
@@ -101,11 +192,21 @@ func (p *Plugin) Validate() error {
 		return p.Upload.Validate()
 	default:
 		return fmt.Errorf(
-			"%w: %s (Valid actions: %s, %s, %s, %s)",
+			"%w: %s (Valid actions: %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)",
 			ErrInvalidAction,
 			p.Config.Action,
+			assertPropsAction,
 			copyAction,
 			deleteAction,
+			dockerCleanupAction,
+			dockerPromoteAction,
+			getPropAction,
+			helmPublishAction,
+			mavenDeployAction,
+			moveAction,
+			ociPushAction,
+			pingAction,
+			searchAction,
 			setPropAction,
 			uploadAction,
 		)
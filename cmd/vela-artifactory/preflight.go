@@ -0,0 +1,389 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/sirupsen/logrus"
+)
+
+const pingAction = "ping"
+
+const (
+	// permRead is the Artifactory permission for reading artifacts.
+	permRead = "r"
+	// permDeploy is the Artifactory permission for deploying artifacts.
+	permDeploy = "w"
+	// permDelete is the Artifactory permission for deleting or overwriting artifacts.
+	permDelete = "d"
+	// permAnnotate is the Artifactory permission for setting properties on artifacts.
+	permAnnotate = "n"
+)
+
+const (
+	// checkPassed represents a pre-flight check that succeeded.
+	checkPassed = "passed"
+	// checkFailed represents a pre-flight check that failed.
+	checkFailed = "failed"
+	// checkSkipped represents a pre-flight check that could not be verified.
+	checkSkipped = "skipped"
+)
+
+// ErrPreflight defines the error type when the
+// pre-flight checks for the plugin fail.
+var ErrPreflight = errors.New("pre-flight checks failed")
+
+// permissionNames maps Artifactory permissions to a human readable name.
+var permissionNames = map[string]string{
+	permRead:     "read",
+	permDeploy:   "deploy",
+	permDelete:   "delete",
+	permAnnotate: "annotate",
+}
+
+// preflightCheck represents the result of a single pre-flight check.
+type preflightCheck struct {
+	// Name of the pre-flight check
+	Name string
+	// Status of the pre-flight check (passed, failed or skipped)
+	Status string
+	// Detail describing the outcome of the pre-flight check
+	Detail string
+}
+
+// repoPermission represents a permission required on a repository.
+type repoPermission struct {
+	// Repo is the repository key in Artifactory
+	Repo string
+	// Permission is the Artifactory permission required on the repository
+	Permission string
+}
+
+// effectivePermissions represents the effective permissions for an item in Artifactory.
+type effectivePermissions struct {
+	Principals struct {
+		Users  map[string][]string `json:"users"`
+		Groups map[string][]string `json:"groups"`
+	} `json:"principals"`
+}
+
+// Preflight verifies connectivity, credentials and permissions
+// for the action before performing it against Artifactory.
+func (p *Plugin) Preflight(cli artifactory.ArtifactoryServicesManager) error {
+	logrus.Debug("running pre-flight checks with provided configuration")
+
+	checks := []*preflightCheck{
+		p.checkPing(cli),
+		p.checkVersion(cli),
+		p.checkCredentials(cli),
+	}
+
+	// only verify permissions when the instance is reachable and credentials are accepted
+	if !slices.ContainsFunc(checks, func(c *preflightCheck) bool { return c.Status == checkFailed }) {
+		for _, rp := range p.permissions() {
+			checks = append(checks, p.checkPermission(cli, rp))
+		}
+	}
+
+	var failed []string
+
+	logrus.Infof("Pre-flight report for %s action against %s:", p.Config.Action, p.Config.URL)
+
+	// report the outcome of every pre-flight check
+	for _, check := range checks {
+		entry := logrus.WithFields(logrus.Fields{
+			"action": p.Config.Action,
+			"check":  check.Name,
+			"status": check.Status,
+		})
+
+		switch check.Status {
+		case checkFailed:
+			failed = append(failed, check.Name)
+
+			entry.Errorf("  [%s] %s: %s", check.Status, check.Name, check.Detail)
+		case checkSkipped:
+			entry.Warnf("  [%s] %s: %s", check.Status, check.Name, check.Detail)
+		default:
+			entry.Infof("  [%s] %s: %s", check.Status, check.Name, check.Detail)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrPreflight, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// checkPing verifies the Artifactory instance is reachable.
+func (p *Plugin) checkPing(cli artifactory.ArtifactoryServicesManager) *preflightCheck {
+	check := &preflightCheck{Name: "ping"}
+
+	// send API call to ping the Artifactory instance
+	_, err := cli.Ping()
+	if err != nil {
+		check.Status = checkFailed
+		check.Detail = fmt.Sprintf("unable to reach %s: %v", p.Config.URL, err)
+
+		return check
+	}
+
+	check.Status = checkPassed
+	check.Detail = fmt.Sprintf("%s is reachable", p.Config.URL)
+
+	return check
+}
+
+// checkVersion captures the version of the Artifactory instance.
+func (p *Plugin) checkVersion(cli artifactory.ArtifactoryServicesManager) *preflightCheck {
+	check := &preflightCheck{Name: "version"}
+
+	// send API call to capture the version of the Artifactory instance
+	version, err := cli.GetVersion()
+	if err != nil {
+		check.Status = checkFailed
+		check.Detail = fmt.Sprintf("unable to capture version: %v", err)
+
+		return check
+	}
+
+	check.Status = checkPassed
+	check.Detail = fmt.Sprintf("Artifactory %s", version)
+
+	return check
+}
+
+// checkCredentials verifies the credentials are accepted by the Artifactory instance.
+func (p *Plugin) checkCredentials(cli artifactory.ArtifactoryServicesManager) *preflightCheck {
+	check := &preflightCheck{Name: "credentials"}
+
+	method, err := p.Config.ResolveAuthMethod()
+	if err != nil {
+		check.Status = checkSkipped
+		check.Detail = fmt.Sprintf("no credentials to verify: %v", err)
+
+		return check
+	}
+
+	// send API call to list repositories, which rejects invalid credentials
+	resp, body, err := apiGet(cli, "api/repositories")
+	if err != nil {
+		check.Status = checkFailed
+		check.Detail = fmt.Sprintf("unable to verify %s credentials: %v", method, err)
+
+		return check
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		check.Status = checkFailed
+		check.Detail = fmt.Sprintf("%s credentials rejected: %s", method, resp.Status)
+
+		return check
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		// only a successful response confirms the credentials are accepted
+		check.Status = checkFailed
+		check.Detail = fmt.Sprintf("unable to verify %s credentials: %s %s", method, resp.Status, strings.TrimSpace(string(body)))
+
+		return check
+	}
+
+	check.Status = checkPassed
+	check.Detail = fmt.Sprintf("authenticated as %s using %s", p.Config.Identity(), method)
+
+	return check
+}
+
+// checkPermission verifies the identity holds the permission on the repository.
+func (p *Plugin) checkPermission(cli artifactory.ArtifactoryServicesManager, rp repoPermission) *preflightCheck {
+	check := &preflightCheck{
+		Name: fmt.Sprintf("%s permission on %s", permissionNames[rp.Permission], rp.Repo),
+	}
+
+	// send API call to capture the effective permissions on the repository
+	resp, body, err := apiGet(cli, fmt.Sprintf("api/storage/%s?permissions", rp.Repo))
+	if err != nil {
+		check.Status = checkFailed
+		check.Detail = fmt.Sprintf("unable to capture permissions: %v", err)
+
+		return check
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		check.Status = checkFailed
+		check.Detail = fmt.Sprintf("repository %s does not exist", rp.Repo)
+
+		return check
+	default:
+		// capturing effective permissions requires the manage permission
+		check.Status = checkSkipped
+		check.Detail = fmt.Sprintf("unable to capture permissions: %s", resp.Status)
+
+		return check
+	}
+
+	permissions := new(effectivePermissions)
+
+	err = json.Unmarshal(body, permissions)
+	if err != nil {
+		check.Status = checkSkipped
+		check.Detail = fmt.Sprintf("unable to parse permissions: %v", err)
+
+		return check
+	}
+
+	identity := p.Config.knownIdentity()
+
+	// the permissions of an identity that cannot be resolved are unknown
+	if len(identity) == 0 {
+		check.Status = checkSkipped
+		check.Detail = fmt.Sprintf("unable to verify %s for %s", permissionNames[rp.Permission], p.Config.Identity())
+
+		return check
+	}
+
+	perms, listed := permissions.Principals.Users[identity]
+
+	// check if the identity is directly granted the permission
+	if slices.Contains(perms, rp.Permission) {
+		check.Status = checkPassed
+		check.Detail = fmt.Sprintf("%s is granted %s", identity, permissionNames[rp.Permission])
+
+		return check
+	}
+
+	// group membership is unknown, so verify a group could grant the permission
+	for group, perms := range permissions.Principals.Groups {
+		if slices.Contains(perms, rp.Permission) {
+			check.Status = checkSkipped
+			check.Detail = fmt.Sprintf("%s may be granted %s through group %s", identity, permissionNames[rp.Permission], group)
+
+			return check
+		}
+	}
+
+	// admins are granted every permission without being listed in the principals
+	if !listed {
+		check.Status = checkSkipped
+		check.Detail = fmt.Sprintf("%s is not listed in the permissions for %s", identity, rp.Repo)
+
+		return check
+	}
+
+	check.Status = checkFailed
+	check.Detail = fmt.Sprintf("%s is not granted %s", identity, permissionNames[rp.Permission])
+
+	return check
+}
+
+// permissions returns the repository permissions required to perform the action.
+func (p *Plugin) permissions() []repoPermission {
+	var perms []repoPermission
+
+	switch p.Config.Action {
+	case assertPropsAction:
+		perms = append(perms,
+			repoPermission{Repo: repoFromPath(p.AssertProps.Path), Permission: permRead},
+		)
+	case copyAction:
+		perms = append(perms,
+			repoPermission{Repo: repoFromPath(p.Copy.Path), Permission: permRead},
+			repoPermission{Repo: repoFromPath(p.Copy.Target), Permission: permDeploy},
+		)
+	case deleteAction:
+		perms = append(perms,
+			repoPermission{Repo: repoFromPath(p.Delete.Path), Permission: permDelete},
+		)
+	case dockerCleanupAction:
+		perms = append(perms,
+			repoPermission{Repo: p.DockerCleanup.Repo, Permission: permRead},
+			repoPermission{Repo: p.DockerCleanup.Repo, Permission: permDelete},
+		)
+	case dockerPromoteAction:
+		source := p.DockerPromote.SourceRepo
+		if len(source) == 0 {
+			source = p.DockerPromote.TargetRepo
+		}
+
+		perms = append(perms,
+			repoPermission{Repo: source, Permission: permRead},
+			repoPermission{Repo: p.DockerPromote.TargetRepo, Permission: permDeploy},
+		)
+
+		// moving an image removes it from the source repository
+		if !p.DockerPromote.Copy {
+			perms = append(perms, repoPermission{Repo: source, Permission: permDelete})
+		}
+
+		// rolling back removes the images promoted to the target repository
+		if p.DockerPromote.Rollback {
+			perms = append(perms, repoPermission{Repo: p.DockerPromote.TargetRepo, Permission: permDelete})
+		}
+
+		if p.DockerPromote.PromoteProperty || len(p.DockerPromote.Props) > 0 {
+			perms = append(perms, repoPermission{Repo: p.DockerPromote.TargetRepo, Permission: permAnnotate})
+		}
+	case getPropAction:
+		perms = append(perms,
+			repoPermission{Repo: repoFromPath(p.GetProp.Path), Permission: permRead},
+		)
+	case helmPublishAction:
+		perms = append(perms,
+			repoPermission{Repo: repoFromPath(p.HelmPublish.Path), Permission: permDeploy},
+		)
+	case mavenDeployAction:
+		perms = append(perms,
+			repoPermission{Repo: p.MavenDeploy.Repo, Permission: permDeploy},
+		)
+	case moveAction:
+		perms = append(perms,
+			repoPermission{Repo: repoFromPath(p.Move.Path), Permission: permRead},
+			repoPermission{Repo: repoFromPath(p.Move.Path), Permission: permDelete},
+			repoPermission{Repo: repoFromPath(p.Move.Target), Permission: permDeploy},
+		)
+	case ociPushAction:
+		perms = append(perms,
+			repoPermission{Repo: p.OCIPush.TargetRepo, Permission: permDeploy},
+		)
+
+		if len(p.OCIPush.RawProps) > 0 || len(p.OCIPush.Props) > 0 {
+			perms = append(perms, repoPermission{Repo: p.OCIPush.TargetRepo, Permission: permAnnotate})
+		}
+	case searchAction:
+		perms = append(perms,
+			repoPermission{Repo: repoFromPath(p.Search.Path), Permission: permRead},
+		)
+	case setPropAction:
+		perms = append(perms,
+			repoPermission{Repo: repoFromPath(p.SetProp.Path), Permission: permAnnotate},
+		)
+	case uploadAction:
+		perms = append(perms,
+			repoPermission{Repo: repoFromPath(p.Upload.Path), Permission: permDeploy},
+		)
+
+		// the structured sources and the files listed in a spec may be uploaded to other repositories
+		sources, _ := p.Upload.entries()
+
+		for _, source := range append(p.Upload.Files, sources...) {
+			rp := repoPermission{Repo: repoFromPath(p.Upload.target(source)), Permission: permDeploy}
+
+			if !slices.Contains(perms, rp) {
+				perms = append(perms, rp)
+			}
+		}
+	}
+
+	// repositories selected by an AQL query are unknown until the artifact(s) are searched for
+	return slices.DeleteFunc(perms, func(rp repoPermission) bool { return len(rp.Repo) == 0 })
+}
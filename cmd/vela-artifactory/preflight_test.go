@@ -0,0 +1,393 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_Plugin_Exec_Ping(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "ping",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+}
+
+func TestArtifactory_Plugin_Exec_Ping_Unauthorized(t *testing.T) {
+	// setup types
+	e := gin.New()
+
+	e.GET("/api/system/ping", func(c *gin.Context) { c.String(200, "OK") })
+	e.GET("/api/system/version", func(c *gin.Context) { c.String(200, `{"version": "7.63.12"}`) })
+	e.GET("/api/repositories", func(c *gin.Context) { c.Status(401) })
+
+	s := httptest.NewServer(e)
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "ping",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            0,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+	}
+
+	err := p.Exec()
+	if !errors.Is(err, ErrPreflight) {
+		t.Errorf("Exec returned err %v, want %v", err, ErrPreflight)
+	}
+}
+
+func TestArtifactory_Plugin_Exec_Preflight(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:    "copy",
+			Preflight: true,
+			URL:       s.URL,
+			Username:  mock.Username,
+			Password:  mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Copy: &Copy{
+			Path:   "foo/bar",
+			Target: "bar/foo",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+}
+
+func TestArtifactory_Plugin_Exec_Preflight_MissingPermission(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:    "delete",
+			Preflight: true,
+			URL:       s.URL,
+			Username:  mock.Username,
+			Password:  mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Delete: &Delete{
+			Path: "read-only/bar",
+		},
+	}
+
+	err := p.Exec()
+	if !errors.Is(err, ErrPreflight) {
+		t.Errorf("Exec returned err %v, want %v", err, ErrPreflight)
+	}
+}
+
+func TestArtifactory_Plugin_Exec_Preflight_RepoNotFound(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:    "upload",
+			Preflight: true,
+			URL:       s.URL,
+			Username:  mock.Username,
+			Password:  mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Upload: &Upload{
+			Path:    "not-found/bar/",
+			Sources: []string{"baz.txt"},
+		},
+	}
+
+	err := p.Exec()
+	if !errors.Is(err, ErrPreflight) {
+		t.Errorf("Exec returned err %v, want %v", err, ErrPreflight)
+	}
+}
+
+func TestArtifactory_Plugin_permissions(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Config: &Config{
+			Action: "docker-promote",
+		},
+		DockerPromote: &DockerPromote{
+			SourceRepo:      "docker-dev",
+			TargetRepo:      "docker-prod",
+			PromoteProperty: true,
+		},
+	}
+
+	want := []repoPermission{
+		{Repo: "docker-dev", Permission: permRead},
+		{Repo: "docker-prod", Permission: permDeploy},
+		{Repo: "docker-dev", Permission: permDelete},
+		{Repo: "docker-prod", Permission: permAnnotate},
+	}
+
+	got := p.permissions()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("permissions is %v, want %v", got, want)
+	}
+}
+
+func TestArtifactory_Plugin_permissions_Aql(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Config: &Config{
+			Action: "copy",
+		},
+		Copy: &Copy{
+			Selection: Selection{Aql: `{"repo":{"$match":"*-snapshot"}}`},
+			Target:    "libs-release-local/app",
+		},
+	}
+
+	want := []repoPermission{
+		{Repo: "libs-release-local", Permission: permDeploy},
+	}
+
+	got := p.permissions()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("permissions is %v, want %v", got, want)
+	}
+}
+
+func TestArtifactory_Plugin_permissions_Move(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Config: &Config{
+			Action: "move",
+		},
+		Move: &Move{
+			Path:   "libs-snapshot-local/app/1.0.0",
+			Target: "libs-release-local/app/1.0.0",
+		},
+	}
+
+	want := []repoPermission{
+		{Repo: "libs-snapshot-local", Permission: permRead},
+		{Repo: "libs-snapshot-local", Permission: permDelete},
+		{Repo: "libs-release-local", Permission: permDeploy},
+	}
+
+	got := p.permissions()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("permissions is %v, want %v", got, want)
+	}
+}
+
+func TestArtifactory_Plugin_permissions_UploadSources(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Config: &Config{
+			Action: "upload",
+		},
+		Upload: &Upload{
+			Path:    "libs-release-local/app/",
+			Sources: []string{`["dist/*.tgz"`, `{"pattern":"docs/**"`, `"target":"docs-local/app/"}]`},
+		},
+	}
+
+	want := []repoPermission{
+		{Repo: "libs-release-local", Permission: permDeploy},
+		{Repo: "docs-local", Permission: permDeploy},
+	}
+
+	got := p.permissions()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("permissions is %v, want %v", got, want)
+	}
+}
+
+func TestArtifactory_Plugin_checkPermission(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	// setup tests
+	tests := []struct {
+		name   string
+		config *Config
+		repo   string
+		want   string
+	}{
+		{
+			name:   "granted",
+			config: &Config{Username: mock.Username, Password: mock.Password},
+			repo:   "foo",
+			want:   checkPassed,
+		},
+		{
+			name:   "not granted",
+			config: &Config{Username: mock.Username, Password: mock.Password},
+			repo:   "read-only",
+			want:   checkFailed,
+		},
+		{
+			// an access token without a subject does not resolve to an identity
+			name:   "unknown identity",
+			config: &Config{Token: mock.Token},
+			repo:   "read-only",
+			want:   checkSkipped,
+		},
+		{
+			// admins are not listed in the principals of a repository
+			name:   "not listed",
+			config: &Config{Username: "admin", Password: mock.Password},
+			repo:   "read-only",
+			want:   checkSkipped,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.Action = "delete"
+			test.config.URL = s.URL
+			test.config.Client = &Client{Retries: 3, RetryWaitMilliSecs: 1}
+
+			p := &Plugin{Config: test.config}
+
+			cli, err := p.Config.New()
+			if err != nil {
+				t.Fatalf("unable to create Artifactory client: %v", err)
+			}
+
+			got := p.checkPermission(*cli, repoPermission{Repo: test.repo, Permission: permDelete})
+
+			if got.Status != test.want {
+				t.Errorf("checkPermission is %s (%s), want %s", got.Status, got.Detail, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Plugin_checkCredentials(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		status int
+		want   string
+	}{
+		{
+			name:   "ok",
+			status: http.StatusOK,
+			want:   checkPassed,
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			want:   checkFailed,
+		},
+		{
+			name:   "forbidden",
+			status: http.StatusForbidden,
+			want:   checkFailed,
+		},
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+			want:   checkFailed,
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			want:   checkFailed,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := gin.New()
+
+			e.GET("/api/repositories", func(c *gin.Context) { c.String(test.status, "[]") })
+
+			s := httptest.NewServer(e)
+			defer s.Close()
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "ping",
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            0,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+			}
+
+			cli, err := p.Config.New()
+			if err != nil {
+				t.Fatalf("unable to create Artifactory client: %v", err)
+			}
+
+			got := p.checkCredentials(*cli)
+
+			if got.Status != test.want {
+				t.Errorf("checkCredentials is %s (%s), want %s", got.Status, got.Detail, test.want)
+			}
+		})
+	}
+}
@@ -4,17 +4,33 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	json "github.com/ghodss/yaml"
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
 	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
 	"github.com/sirupsen/logrus"
 )
 
 const setPropAction = "set-prop"
 
+const (
+	// setPropScopeContents sets the properties on the artifact(s) matching the path.
+	setPropScopeContents = "contents"
+	// setPropScopeFolders sets the properties on the folder(s) matching the path.
+	setPropScopeFolders = "folders"
+	// setPropScopeBoth sets the properties on the artifact(s) and folder(s) matching the path.
+	setPropScopeBoth = "both"
+)
+
+// setPropScopes are the supported scopes for setting properties.
+var setPropScopes = []string{setPropScopeContents, setPropScopeFolders, setPropScopeBoth}
+
 // Prop represents the plugin configuration for setting a property.
 type Prop struct {
 	// name of the property to set on the artifact(s)
@@ -23,6 +39,10 @@ type Prop struct {
 	Value string
 	// values of the property to set on the artifact(s)
 	Values []string
+	// file in the workspace containing the value of the property to set on the artifact(s)
+	File string
+	// mode for combining the values with the current values of the property on the artifact(s)
+	Mode string
 }
 
 // String formats and returns a query string for the property.
@@ -31,10 +51,17 @@ func (p *Prop) String() string {
 
 	// check if property value is provided
 	if len(p.Value) > 0 {
-		return fmt.Sprintf("%s=%s", p.Name, p.Value)
+		return fmt.Sprintf("%s=%s", p.Name, escapePropValue(p.Value))
+	}
+
+	values := make([]string, 0, len(p.Values))
+
+	// escape the separators in every property value
+	for _, value := range p.Values {
+		values = append(values, escapePropValue(value))
 	}
 
-	return fmt.Sprintf("%s=%s", p.Name, strings.Join(p.Values, ","))
+	return fmt.Sprintf("%s=%s", p.Name, strings.Join(values, ","))
 }
 
 // Validate verifies the Prop is properly configured.
@@ -46,16 +73,45 @@ func (p *Prop) Validate() error {
 		return fmt.Errorf("no prop name provided")
 	}
 
+	// verify name does not contain separators
+	if strings.ContainsAny(p.Name, propNameChars) {
+		return fmt.Errorf("prop name %s must not contain any of %s", p.Name, propNameChars)
+	}
+
+	// verify mode is supported
+	if len(p.Mode) > 0 && !slices.Contains(propModes, p.Mode) {
+		return fmt.Errorf("invalid prop %s mode %s provided (Valid modes: %s)", p.Name, p.Mode, strings.Join(propModes, ", "))
+	}
+
+	// check if the value is read from a file
+	if len(p.File) > 0 {
+		// verify value or values are not also provided
+		if len(p.Value) > 0 || len(p.Values) > 0 {
+			return fmt.Errorf("prop %s file is mutually exclusive with value and values", p.Name)
+		}
+
+		// verify the file exists
+		_, err := os.Stat(p.File)
+		if err != nil {
+			return fmt.Errorf("unable to read prop %s file: %w", p.Name, err)
+		}
+
+		return nil
+	}
+
 	// verify value or values are provided
 	if len(p.Value) == 0 && len(p.Values) == 0 {
-		return fmt.Errorf("no prop value or values provided")
+		return fmt.Errorf("no prop value, values or file provided")
 	}
 
-	return nil
+	// verify the values can be separated from the next value or property
+	return p.validatePropValues()
 }
 
 // SetProp represents the plugin configuration for setting property information.
 type SetProp struct {
+	Selection
+
 	// Path is the target path to artifact(s) to set properties
 	Path string
 	// Props are properties to set on the artifact(s)
@@ -64,20 +120,23 @@ type SetProp struct {
 	RawProps string
 	// Recursive is a flag that enables setting properties on sub-directories for the artifact(s) in the path
 	Recursive bool
+	// Scope is the type of item(s) matching the path to set properties on
+	Scope string
 }
 
 // Exec formats and runs the commands for setting properties on artifacts in Artifactory.
 func (s *SetProp) Exec(cli artifactory.ArtifactoryServicesManager) error {
 	logrus.Trace("running set-prop with provided configuration")
 
+	start := time.Now()
+	logger := actionLogger(setPropAction, s.Path)
+
 	// create new search parameters
 	searchParams := services.NewSearchParams()
 
 	// add search configuration to search parameters
-	searchParams.CommonParams = &utils.CommonParams{
-		Pattern:   s.Path,
-		Recursive: s.Recursive,
-	}
+	searchParams.CommonParams = s.CommonParams(s.Path, s.Recursive)
+	searchParams.IncludeDirs = s.Scope == setPropScopeFolders || s.Scope == setPropScopeBoth
 
 	// send API call to search path for artifacts in Artifactory
 	files, err := cli.SearchFiles(searchParams)
@@ -85,22 +144,107 @@ func (s *SetProp) Exec(cli artifactory.ArtifactoryServicesManager) error {
 		return err
 	}
 
+	defer files.Close()
+
+	// check if the properties are only set on folders
+	if s.Scope == setPropScopeFolders {
+		folders, err := filterFolders(files)
+		if err != nil {
+			return err
+		}
+
+		defer folders.Close()
+
+		files = folders
+	}
+
+	// log the artifact(s) selected for setting properties
+	err = s.logSelection(logger, files)
+	if err != nil {
+		return err
+	}
+
+	build := newBuildMetadata()
+
+	// check if a property mode depends on the current values of the property
+	if s.hasModes() {
+		var rendered []*Prop
+
+		// render each property using the build information
+		for _, prop := range s.Props {
+			r, err := renderProp(prop, build)
+			if err != nil {
+				return err
+			}
+
+			rendered = append(rendered, r)
+		}
+
+		return s.execModes(cli, logger, start, files, rendered)
+	}
+
+	// render each property using the build information
+	props, err := renderProps(s.Props, build)
+	if err != nil {
+		return err
+	}
+
+	// the client library sets properties even when dry run is enabled
+	if s.DryRun {
+		items, err := files.Length()
+		if err != nil {
+			return err
+		}
+
+		withDuration(logger, start).Infof("Dry run enabled, skipping setting properties [%s] on %d item(s)", strings.Join(props, ";"), items)
+
+		return nil
+	}
+
 	// create new property parameters
 	p := services.NewPropsParams()
 
 	// add property configuration to property parameters
 	p.Reader = files
-	p.Props = s.String()
+	p.Props = strings.Join(props, ";")
 
 	// send API call to set properties for artifacts in Artifactory
-	_, err = cli.SetProps(p)
+	success, err := cli.SetProps(p)
 	if err != nil {
 		return err
 	}
 
+	withDuration(logger, start).WithField("success", success).Infof("Set properties on %d artifact(s)", success)
+
 	return nil
 }
 
+// filterFolders returns a reader with only the folder(s) from the search results.
+func filterFolders(reader *content.ContentReader) (*content.ContentReader, error) {
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for item := new(utils.ResultItem); reader.NextRecord(item) == nil; item = new(utils.ResultItem) {
+		if item.Type == "folder" {
+			writer.Write(*item)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = reader.GetError()
+	if err != nil {
+		return nil, err
+	}
+
+	return content.NewContentReader(writer.GetFilePath(), content.DefaultKey), nil
+}
+
 // String formats and returns a query string for the properties.
 func (s *SetProp) String() string {
 	logrus.Trace("creating string for props")
@@ -138,13 +282,25 @@ func (s *SetProp) Unmarshal() error {
 func (s *SetProp) Validate() error {
 	logrus.Trace("validating set prop plugin configuration")
 
-	// verify path is provided
-	if len(s.Path) == 0 {
-		return fmt.Errorf("no set-prop path provided")
+	// verify the selection is valid
+	err := s.Selection.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid set-prop selection provided: %w", err)
+	}
+
+	// verify path or aql is provided
+	err = s.validatePath(setPropAction, s.Path)
+	if err != nil {
+		return err
+	}
+
+	// verify scope is supported
+	if len(s.Scope) > 0 && !slices.Contains(setPropScopes, s.Scope) {
+		return fmt.Errorf("invalid set-prop scope %s provided (Valid scopes: %s)", s.Scope, strings.Join(setPropScopes, ", "))
 	}
 
 	// serialize provided properties into expected type
-	err := s.Unmarshal()
+	err = s.Unmarshal()
 	if err != nil {
 		return fmt.Errorf("unable to unmarshal set-prop props: %w", err)
 	}
@@ -154,13 +310,10 @@ func (s *SetProp) Validate() error {
 		return fmt.Errorf("no set-prop props provided")
 	}
 
-	// iterate through all properties
-	for _, prop := range s.Props {
-		// verify the property is valid
-		err := prop.Validate()
-		if err != nil {
-			return fmt.Errorf("invalid set-prop prop provided: %w", err)
-		}
+	// verify the properties and their templates are valid
+	err = validatePropTemplates(s.Props)
+	if err != nil {
+		return fmt.Errorf("invalid set-prop prop provided: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,265 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// propModeSet replaces the values of the property.
+	propModeSet = "set"
+	// propModeAppend adds the values to the current values of the property.
+	propModeAppend = "append"
+	// propModeRemove removes the values from the current values of the property.
+	propModeRemove = "remove"
+	// propModeSetIfAbsent sets the values of the property when it is not already set.
+	propModeSetIfAbsent = "set-if-absent"
+)
+
+// propModes are the supported modes for setting a property.
+var propModes = []string{propModeSet, propModeAppend, propModeRemove, propModeSetIfAbsent}
+
+// propChange represents the changes to the properties of an artifact.
+type propChange struct {
+	// Path is the path to the artifact in Artifactory
+	Path string
+	// Set are the properties to set on the artifact
+	Set []*Prop
+	// Remove are the names of the properties to remove from the artifact
+	Remove []string
+}
+
+// String formats and returns a summary of the property changes.
+func (c *propChange) String() string {
+	var changes []string
+
+	for _, prop := range c.Set {
+		changes = append(changes, fmt.Sprintf("set %s=%s", prop.Name, strings.Join(prop.Values, ",")))
+	}
+
+	for _, name := range c.Remove {
+		changes = append(changes, fmt.Sprintf("remove %s", name))
+	}
+
+	return strings.Join(changes, "; ")
+}
+
+// values returns the values of the property.
+func (p *Prop) values() []string {
+	if len(p.Value) > 0 {
+		return []string{p.Value}
+	}
+
+	return p.Values
+}
+
+// apply returns the values of the property after applying its mode to the current values.
+func (p *Prop) apply(current []string) []string {
+	switch p.Mode {
+	case propModeAppend:
+		values := slices.Clone(current)
+
+		for _, value := range p.values() {
+			if !slices.Contains(values, value) {
+				values = append(values, value)
+			}
+		}
+
+		return values
+	case propModeRemove:
+		return slices.DeleteFunc(slices.Clone(current), func(value string) bool {
+			return slices.Contains(p.values(), value)
+		})
+	case propModeSetIfAbsent:
+		if len(current) > 0 {
+			return current
+		}
+
+		return p.values()
+	default:
+		return p.values()
+	}
+}
+
+// hasModes returns true when a property mode depends on the current values of the property.
+func (s *SetProp) hasModes() bool {
+	return slices.ContainsFunc(s.Props, func(prop *Prop) bool {
+		return len(prop.Mode) > 0 && prop.Mode != propModeSet
+	})
+}
+
+// planPropChange returns the changes to the properties of the artifact,
+// or nil when the artifact already has the desired properties.
+func planPropChange(item *utils.ResultItem, props []*Prop) *propChange {
+	current := make(map[string][]string)
+
+	for _, prop := range item.Properties {
+		current[prop.Key] = append(current[prop.Key], prop.Value)
+	}
+
+	change := &propChange{Path: item.GetItemRelativePath()}
+
+	for _, prop := range props {
+		values := prop.apply(current[prop.Name])
+
+		// skip the property when the values are unchanged
+		if slices.Equal(sortedCopy(values), sortedCopy(current[prop.Name])) {
+			continue
+		}
+
+		// remove the property when no values remain
+		if len(values) == 0 {
+			change.Remove = append(change.Remove, prop.Name)
+		} else {
+			change.Set = append(change.Set, &Prop{Name: prop.Name, Values: values})
+		}
+
+		current[prop.Name] = values
+	}
+
+	if len(change.Set) == 0 && len(change.Remove) == 0 {
+		return nil
+	}
+
+	return change
+}
+
+// sortedCopy returns a sorted copy of the values.
+func sortedCopy(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	return sorted
+}
+
+// execModes computes the desired properties for every artifact from its current
+// properties, and only updates the artifact(s) with properties that change.
+func (s *SetProp) execModes(
+	cli artifactory.ArtifactoryServicesManager,
+	logger *logrus.Entry,
+	start time.Time,
+	files *content.ContentReader,
+	props []*Prop,
+) error {
+	var changed, unchanged int
+
+	for item := new(utils.ResultItem); files.NextRecord(item) == nil; item = new(utils.ResultItem) {
+		change := planPropChange(item, props)
+		if change == nil {
+			logger.Debugf("  [unchanged] %s", item.GetItemRelativePath())
+
+			unchanged++
+
+			continue
+		}
+
+		if s.DryRun {
+			logger.Infof("  [dry run] %s: %s", change.Path, change)
+		} else {
+			logger.Infof("  [changed] %s: %s", change.Path, change)
+
+			err := change.apply(cli)
+			if err != nil {
+				return err
+			}
+		}
+
+		changed++
+	}
+
+	err := files.GetError()
+	if err != nil {
+		return err
+	}
+
+	withDuration(logger, start).WithFields(logrus.Fields{
+		"success":   changed,
+		"unchanged": unchanged,
+	}).Infof("Set properties on %d artifact(s), %d artifact(s) unchanged", changed, unchanged)
+
+	return nil
+}
+
+// storagePath returns the storage API path for the artifact, without
+// the trailing slash Artifactory includes in the path of a folder.
+// Each segment of the path is escaped, since artifact names may
+// contain characters that are reserved in a URL (e.g. # or ?).
+func (c *propChange) storagePath() string {
+	var segments []string
+
+	for _, segment := range strings.Split(path.Clean(c.Path), "/") {
+		segments = append(segments, url.PathEscape(segment))
+	}
+
+	return path.Join("api", "storage", strings.Join(segments, "/"))
+}
+
+// apply sends the property changes for the artifact to Artifactory.
+func (c *propChange) apply(cli artifactory.ArtifactoryServicesManager) error {
+	if len(c.Set) > 0 {
+		var set []string
+
+		for _, prop := range c.Set {
+			set = append(set, prop.String())
+		}
+
+		parsed, err := utils.ParseProperties(strings.Join(set, ";"))
+		if err != nil {
+			return fmt.Errorf("unable to parse properties for %s: %w", c.Path, err)
+		}
+
+		// send API call to set the properties on the artifact, without the items within a folder
+		resp, body, err := apiPut(cli, fmt.Sprintf("%s?properties=%s&recursive=0", c.storagePath(), parsed.ToEncodedString(true)))
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unable to set properties on %s: %s %s", c.Path, resp.Status, string(body))
+		}
+	}
+
+	if len(c.Remove) > 0 {
+		var names []string
+
+		for _, name := range c.Remove {
+			names = append(names, url.QueryEscape(name))
+		}
+
+		// send API call to remove the properties from the artifact, without the items within a folder
+		resp, body, err := apiDelete(cli, fmt.Sprintf("%s?properties=%s&recursive=0", c.storagePath(), strings.Join(names, ",")))
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unable to remove properties from %s: %s %s", c.Path, resp.Status, string(body))
+		}
+	}
+
+	return nil
+}
+
+// validateSetMode verifies the properties only replace their values, for
+// actions that set properties without reading the current values.
+func validateSetMode(props []*Prop) error {
+	for _, prop := range props {
+		if len(prop.Mode) > 0 && prop.Mode != propModeSet {
+			return fmt.Errorf("prop %s mode %s is not supported (Valid modes: %s)", prop.Name, prop.Mode, propModeSet)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,310 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_Prop_apply(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		prop    *Prop
+		current []string
+		want    []string
+	}{
+		{
+			name:    "set",
+			prop:    &Prop{Name: "os", Values: []string{"linux"}},
+			current: []string{"linux", "darwin"},
+			want:    []string{"linux"},
+		},
+		{
+			name:    "append",
+			prop:    &Prop{Name: "os", Values: []string{"darwin", "windows"}, Mode: propModeAppend},
+			current: []string{"linux", "darwin"},
+			want:    []string{"linux", "darwin", "windows"},
+		},
+		{
+			name:    "remove",
+			prop:    &Prop{Name: "os", Value: "darwin", Mode: propModeRemove},
+			current: []string{"linux", "darwin"},
+			want:    []string{"linux"},
+		},
+		{
+			name:    "remove last value",
+			prop:    &Prop{Name: "os", Value: "linux", Mode: propModeRemove},
+			current: []string{"linux"},
+			want:    []string{},
+		},
+		{
+			name:    "set if absent",
+			prop:    &Prop{Name: "os", Value: "linux", Mode: propModeSetIfAbsent},
+			current: nil,
+			want:    []string{"linux"},
+		},
+		{
+			name:    "set if absent with value",
+			prop:    &Prop{Name: "os", Value: "linux", Mode: propModeSetIfAbsent},
+			current: []string{"darwin"},
+			want:    []string{"darwin"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.prop.apply(test.current)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("apply is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_propChange_storagePath(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "artifact",
+			path: "props-local/app/app.tar.gz",
+			want: "api/storage/props-local/app/app.tar.gz",
+		},
+		{
+			name: "folder",
+			path: "props-local/app/",
+			want: "api/storage/props-local/app",
+		},
+		{
+			name: "reserved characters",
+			path: "props-local/app #1/app?.tar.gz",
+			want: "api/storage/props-local/app%20%231/app%3F.tar.gz",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &propChange{Path: test.path}
+
+			got := c.storagePath()
+
+			if got != test.want {
+				t.Errorf("storagePath is %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_planPropChange(t *testing.T) {
+	// setup types
+	item := &utils.ResultItem{
+		Repo: "props-local",
+		Path: "app",
+		Name: "app.tar.gz",
+		Properties: []utils.Property{
+			{Key: "os", Value: "linux"},
+			{Key: "os", Value: "darwin"},
+			{Key: "qa.status", Value: "failed"},
+		},
+	}
+
+	// setup tests
+	tests := []struct {
+		name  string
+		props []*Prop
+		want  *propChange
+	}{
+		{
+			name:  "unchanged",
+			props: []*Prop{{Name: "os", Values: []string{"darwin", "linux"}}, {Name: "qa.status", Value: "failed", Mode: propModeSetIfAbsent}},
+		},
+		{
+			name:  "set and remove",
+			props: []*Prop{{Name: "os", Value: "windows", Mode: propModeAppend}, {Name: "qa.status", Value: "failed", Mode: propModeRemove}},
+			want: &propChange{
+				Path:   "props-local/app/app.tar.gz",
+				Set:    []*Prop{{Name: "os", Values: []string{"linux", "darwin", "windows"}}},
+				Remove: []string{"qa.status"},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := planPropChange(item, test.props)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("planPropChange is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_SetProp_Exec_Modes(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		dryRun bool
+		want   []string
+	}{
+		{
+			name: "modes",
+			want: []string{
+				"PUT /api/storage/props-local/app/app-linux.tar.gz os=linux,darwin;owner=release",
+				"DELETE /api/storage/props-local/app/app-universal.tar.gz qa.status",
+				"PUT /api/storage/props-local/app/app.txt os=darwin;owner=release",
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				requests []string
+			)
+
+			handler := mock.Handlers()
+
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPut || r.Method == http.MethodDelete {
+					// the properties are separated by semicolons, which are not valid in a parsed query
+					query, recursive, _ := strings.Cut(r.URL.RawQuery, "&")
+					props, _ := url.QueryUnescape(strings.TrimPrefix(query, "properties="))
+
+					if recursive != "recursive=0" {
+						t.Errorf("%s %s sent with %s, want recursive=0", r.Method, r.URL.Path, recursive)
+					}
+
+					mu.Lock()
+					requests = append(requests, r.Method+" "+r.URL.Path+" "+props)
+					mu.Unlock()
+				}
+
+				handler.ServeHTTP(w, r)
+			}))
+			defer s.Close()
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "set-prop",
+					DryRun:   test.dryRun,
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				SetProp: &SetProp{
+					Selection: Selection{DryRun: test.dryRun},
+					Path:      "props-local/app/*",
+					RawProps: `
+- name: os
+  value: darwin
+  mode: append
+- name: qa.status
+  value: failed
+  mode: remove
+- name: owner
+  value: release
+  mode: set-if-absent
+`,
+				},
+			}
+
+			err := p.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = p.Exec()
+			if err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(requests, test.want) {
+				t.Errorf("Exec sent requests %v, want %v", requests, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Prop_Validate_Mode(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		prop    *Prop
+		wantErr bool
+	}{
+		{
+			name: "no mode",
+			prop: &Prop{Name: "os", Value: "linux"},
+		},
+		{
+			name: "append",
+			prop: &Prop{Name: "os", Value: "linux", Mode: propModeAppend},
+		},
+		{
+			name:    "invalid mode",
+			prop:    &Prop{Name: "os", Value: "linux", Mode: "merge"},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.prop.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestArtifactory_validateSetMode(t *testing.T) {
+	// setup types
+	props := []*Prop{{Name: "os", Value: "linux", Mode: propModeSet}}
+
+	err := validateSetMode(props)
+	if err != nil {
+		t.Errorf("validateSetMode returned err: %v", err)
+	}
+
+	props = append(props, &Prop{Name: "arch", Value: "amd64", Mode: propModeAppend})
+
+	err = validateSetMode(props)
+	if err == nil {
+		t.Errorf("validateSetMode should have returned err")
+	}
+}
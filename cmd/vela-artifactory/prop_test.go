@@ -3,8 +3,15 @@
 package main
 
 import (
+	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
@@ -314,3 +321,296 @@ func TestArtifactory_SetProp_Unmarshal_FailUnmarshal(t *testing.T) {
 		t.Errorf("Unmarshal should have returned err")
 	}
 }
+
+func TestArtifactory_SetProp_Exec_Template(t *testing.T) {
+	// setup types
+	t.Setenv("VELA_REPO_FULL_NAME", "octocat/hello-world")
+	t.Setenv("VELA_BUILD_COMMIT", "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d")
+	t.Setenv("VELA_BUILD_LINK", "https://vela.example.com/octocat/hello-world/1")
+
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			mu.Lock()
+			// the properties are separated by semicolons, which are not valid in a parsed query
+			props, _, _ := strings.Cut(strings.TrimPrefix(r.URL.RawQuery, "properties="), "&")
+			props, _ = url.QueryUnescape(props)
+
+			queries = append(queries, props)
+			mu.Unlock()
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	file := filepath.Join(t.TempDir(), "notes.txt")
+
+	err := os.WriteFile(file, []byte("tested; approved\n"), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "set-prop",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		SetProp: &SetProp{
+			Path: "foo/bar",
+			RawProps: `
+- name: vcs.repo
+  value: "{{ .Repo }}"
+- name: vcs.commit
+  values:
+    - "{{ .Commit }}"
+    - "{{ .CommitShort }}"
+- name: build.link
+  value: "{{ .BuildLink }}?tab=steps"
+- name: notes
+  file: ` + file,
+		},
+	}
+
+	err = p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := `build.link=https://vela.example.com/octocat/hello-world/1?tab\=steps;notes=tested\; approved;` +
+		`vcs.commit=7fd1a60b01f91b314f59955a4e4d4e80d8edf11d,7fd1a60b;vcs.repo=octocat/hello-world`
+
+	if len(queries) == 0 {
+		t.Fatalf("Exec did not set properties")
+	}
+
+	for _, query := range queries {
+		if query != want {
+			t.Errorf("Exec set properties %s, want %s", query, want)
+		}
+	}
+}
+
+func TestArtifactory_SetProp_Exec_Scope(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		scope    string
+		dryRun   bool
+		rawProps string
+		want     []string
+	}{
+		{
+			name:     "contents",
+			rawProps: `[{"name": "release", "value": "true"}]`,
+			want: []string{
+				"/api/storage/folders-local/app/1.0.0/app.tar.gz",
+				"/api/storage/folders-local/app/1.1.0/app.tar.gz",
+			},
+		},
+		{
+			name:     "folders",
+			scope:    setPropScopeFolders,
+			rawProps: `[{"name": "release", "value": "true"}]`,
+			want: []string{
+				"/api/storage/folders-local/app/1.0.0",
+				"/api/storage/folders-local/app/1.1.0",
+			},
+		},
+		{
+			name:     "both",
+			scope:    setPropScopeBoth,
+			rawProps: `[{"name": "release", "value": "true"}]`,
+			want: []string{
+				"/api/storage/folders-local/app/1.0.0",
+				"/api/storage/folders-local/app/1.0.0/app.tar.gz",
+				"/api/storage/folders-local/app/1.1.0",
+				"/api/storage/folders-local/app/1.1.0/app.tar.gz",
+			},
+		},
+		{
+			name:     "folders with mode",
+			scope:    setPropScopeFolders,
+			rawProps: `[{"name": "release", "value": "true", "mode": "append"}]`,
+			want: []string{
+				"/api/storage/folders-local/app/1.0.0",
+				"/api/storage/folders-local/app/1.1.0",
+			},
+		},
+		{
+			name:     "dry run",
+			scope:    setPropScopeBoth,
+			dryRun:   true,
+			rawProps: `[{"name": "release", "value": "true"}]`,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				paths []string
+			)
+
+			handler := mock.Handlers()
+
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPut {
+					mu.Lock()
+					paths = append(paths, r.URL.Path)
+					mu.Unlock()
+				}
+
+				handler.ServeHTTP(w, r)
+			}))
+			defer s.Close()
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "set-prop",
+					DryRun:   test.dryRun,
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				SetProp: &SetProp{
+					Selection: Selection{DryRun: test.dryRun},
+					Path:      "folders-local/app/*",
+					RawProps:  test.rawProps,
+					Recursive: true,
+					Scope:     test.scope,
+				},
+			}
+
+			err := p.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = p.Exec()
+			if err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			// the properties are set concurrently
+			slices.Sort(paths)
+
+			if !reflect.DeepEqual(paths, test.want) {
+				t.Errorf("Exec set properties on %v, want %v", paths, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_SetProp_Validate_Scope(t *testing.T) {
+	// setup types
+	s := &SetProp{
+		Path:     "foo/bar",
+		RawProps: `[{"name": "single", "value": "foo"}]`,
+		Scope:    setPropScopeFolders,
+	}
+
+	err := s.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+
+	s.Scope = "files"
+
+	err = s.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestArtifactory_SetProp_Validate_Props(t *testing.T) {
+	// setup types
+	file := filepath.Join(t.TempDir(), "notes.txt")
+
+	err := os.WriteFile(file, []byte("notes"), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name     string
+		rawProps string
+		wantErr  bool
+	}{
+		{
+			name:     "template",
+			rawProps: `[{"name": "build", "value": "{{ .BuildNumber }}-{{ now.Format \"20060102\" }}"}]`,
+		},
+		{
+			name:     "file",
+			rawProps: `[{"name": "notes", "file": "` + file + `"}]`,
+		},
+		{
+			name:     "typed",
+			rawProps: `[{"name": "count", "value": 17}]`,
+		},
+		{
+			name:     "invalid template",
+			rawProps: `[{"name": "build", "value": "{{ .BuildNumber"}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "separator in name",
+			rawProps: `[{"name": "build=number", "value": "1"}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "file and value",
+			rawProps: `[{"name": "notes", "value": "notes", "file": "` + file + `"}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "missing file",
+			rawProps: `[{"name": "notes", "file": "` + file + `.missing"}]`,
+			wantErr:  true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &SetProp{
+				Path:     "foo/bar",
+				RawProps: test.rawProps,
+			}
+
+			err := s.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// propNameChars are the characters that separate properties
+// and their values, which are not allowed in property names.
+const propNameChars = ";,|="
+
+// propValueEscaper escapes the characters that separate properties and their values
+// in the matrix parameters sent to Artifactory. A backslash is escaped first, so it is
+// not read as the escape of the character following it. The client library removes
+// the escape from a semicolon when parsing the properties, so a semicolon is escaped
+// twice for the escape to reach Artifactory. The client library escapes commas itself
+// when it encodes the parsed properties.
+//
+// The replacer applies every replacement in a single pass, so the escapes added
+// for the separators are not escaped again as backslashes.
+var propValueEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\\;`,
+	",", `\,`,
+	"|", `\|`,
+	"=", `\=`,
+)
+
+// escapePropValue escapes the separator characters in the property value.
+func escapePropValue(value string) string {
+	return propValueEscaper.Replace(value)
+}
+
+// validatePropValues verifies the property values can be sent to Artifactory.
+//
+// The client library reads a separator following a backslash as escaped, without
+// accounting for an escaped backslash, so a value ending with a backslash would
+// merge the next value or property into it.
+func (p *Prop) validatePropValues() error {
+	for _, value := range append([]string{p.Value}, p.Values...) {
+		if strings.HasSuffix(value, `\`) {
+			return fmt.Errorf("prop %s value %s must not end with a backslash", p.Name, value)
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON captures the property from JSON, accepting numbers and
+// booleans as values so they can be provided without quotes in YAML.
+func (p *Prop) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name   string
+		Value  json.RawMessage
+		Values []json.RawMessage
+		File   string
+		Mode   string
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	p.Name = raw.Name
+	p.File = raw.File
+	p.Mode = raw.Mode
+
+	p.Value, err = propScalar(raw.Value)
+	if err != nil {
+		return fmt.Errorf("prop %s value: %w", raw.Name, err)
+	}
+
+	p.Values = nil
+
+	for _, v := range raw.Values {
+		value, err := propScalar(v)
+		if err != nil {
+			return fmt.Errorf("prop %s values: %w", raw.Name, err)
+		}
+
+		p.Values = append(p.Values, value)
+	}
+
+	return nil
+}
+
+// propScalar returns the string form of a JSON string, number or boolean.
+func propScalar(data json.RawMessage) (string, error) {
+	data = bytes.TrimSpace(data)
+
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+
+	var value any
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	err := d.Decode(&value)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("unsupported value %s", string(data))
+	}
+}
+
+// fileValue returns the contents of the property's file without surrounding whitespace.
+func (p *Prop) fileValue() (string, error) {
+	data, err := os.ReadFile(p.File)
+	if err != nil {
+		return "", fmt.Errorf("unable to read prop %s file: %w", p.Name, err)
+	}
+
+	value := strings.TrimSpace(string(data))
+
+	// verify the file contains a value
+	if len(value) == 0 {
+		return "", fmt.Errorf("prop %s file %s is empty", p.Name, p.File)
+	}
+
+	return value, nil
+}
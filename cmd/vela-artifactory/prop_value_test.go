@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	json "github.com/ghodss/yaml"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+)
+
+func TestArtifactory_Prop_String_Escape(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		prop    *Prop
+		want    []string
+		encoded string
+	}{
+		{
+			name:    "value",
+			prop:    &Prop{Name: "note", Value: "a;b,c=d|e"},
+			want:    []string{`a\;b,c\=d\|e`},
+			encoded: `note=a\;b\,c\=d\|e`,
+		},
+		{
+			name:    "backslash",
+			prop:    &Prop{Name: "path", Value: `C:\path;x`},
+			want:    []string{`C:\\path\;x`},
+			encoded: `path=C:\\path\;x`,
+		},
+		{
+			name:    "values",
+			prop:    &Prop{Name: "os", Values: []string{"linux,amd64", "darwin"}},
+			want:    []string{"linux,amd64", "darwin"},
+			encoded: `os=linux\,amd64,darwin`,
+		},
+		{
+			name:    "link",
+			prop:    &Prop{Name: "build.link", Value: "https://vela.example.com/octocat/hello-world/1?tab=steps"},
+			want:    []string{`https://vela.example.com/octocat/hello-world/1?tab\=steps`},
+			encoded: `build.link=https://vela.example.com/octocat/hello-world/1?tab\=steps`,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// parse the property the same way as the client library
+			props, err := utils.ParseProperties(test.prop.String())
+			if err != nil {
+				t.Fatalf("ParseProperties returned err: %v", err)
+			}
+
+			got := props.ToMap()[test.prop.Name]
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseProperties is %v, want %v", got, test.want)
+			}
+
+			encoded, err := url.QueryUnescape(props.ToEncodedString(true))
+			if err != nil {
+				t.Fatalf("QueryUnescape returned err: %v", err)
+			}
+
+			if encoded != test.encoded {
+				t.Errorf("ToEncodedString is %s, want %s", encoded, test.encoded)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Prop_Validate_TrailingBackslash(t *testing.T) {
+	// setup types
+	props := []*Prop{
+		{Name: "path", Value: `C:\dir\`},
+		{Name: "os", Value: "linux"},
+	}
+
+	// the client library reads the separator after the escaped backslash as escaped
+	parsed, err := utils.ParseProperties(props[0].String() + ";" + props[1].String())
+	if err != nil {
+		t.Fatalf("ParseProperties returned err: %v", err)
+	}
+
+	if _, ok := parsed.ToMap()["os"]; ok {
+		t.Errorf("ParseProperties separated the property following a trailing backslash")
+	}
+
+	// setup tests
+	tests := []struct {
+		name string
+		prop *Prop
+	}{
+		{
+			name: "value",
+			prop: props[0],
+		},
+		{
+			name: "values",
+			prop: &Prop{Name: "path", Values: []string{`C:\dir\`, `D:\dir`}},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.prop.Validate()
+			if err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+		})
+	}
+
+	// verify a rendered value ending with a backslash returns an error
+	_, err = renderProp(&Prop{Name: "path", Value: "{{ .Dir }}"}, map[string]string{"Dir": `C:\dir\`})
+	if err == nil {
+		t.Errorf("renderProp should have returned err")
+	}
+}
+
+func TestArtifactory_Prop_Unmarshal(t *testing.T) {
+	// setup types
+	raw := `
+- name: count
+  value: 17
+- name: enabled
+  value: true
+- name: versions
+  values:
+    - 1.2
+    - "1.10"
+    - latest
+- name: notes
+  file: notes.txt
+`
+
+	want := []*Prop{
+		{Name: "count", Value: "17"},
+		{Name: "enabled", Value: "true"},
+		{Name: "versions", Values: []string{"1.2", "1.10", "latest"}},
+		{Name: "notes", File: "notes.txt"},
+	}
+
+	var got []*Prop
+
+	err := json.Unmarshal([]byte(raw), &got)
+	if err != nil {
+		t.Errorf("Unmarshal returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal is %v, want %v", got, want)
+	}
+
+	err = json.Unmarshal([]byte(`[{"name": "nested", "value": {"foo": "bar"}}]`), &got)
+	if err == nil {
+		t.Errorf("Unmarshal should have returned err")
+	}
+}
+
+func TestArtifactory_Prop_fileValue(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "version.txt")
+
+	err := os.WriteFile(file, []byte("1.2.3\n"), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	empty := filepath.Join(dir, "empty.txt")
+
+	err = os.WriteFile(empty, []byte("\n"), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	got, err := (&Prop{Name: "version", File: file}).fileValue()
+	if err != nil {
+		t.Errorf("fileValue returned err: %v", err)
+	}
+
+	if got != "1.2.3" {
+		t.Errorf("fileValue is %s, want 1.2.3", got)
+	}
+
+	_, err = (&Prop{Name: "version", File: empty}).fileValue()
+	if err == nil {
+		t.Errorf("fileValue should have returned err")
+	}
+
+	_, err = (&Prop{Name: "version", File: filepath.Join(dir, "missing.txt")}).fileValue()
+	if err == nil {
+		t.Errorf("fileValue should have returned err")
+	}
+}
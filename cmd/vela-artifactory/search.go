@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const searchAction = "search"
+
+// Search represents the plugin configuration for search information.
+type Search struct {
+	Selection
+
+	// Path is the path to artifact(s) to search for
+	Path string
+	// Recursive is a flag that enables searching sub-directories for the artifact(s) in the path
+	Recursive bool
+}
+
+// Exec formats and runs the commands for searching for artifacts in Artifactory.
+func (s *Search) Exec(cli artifactory.ArtifactoryServicesManager) error {
+	logrus.Trace("running search with provided configuration")
+
+	start := time.Now()
+	logger := actionLogger(searchAction, s.Path)
+
+	// create new search parameters
+	p := services.NewSearchParams()
+
+	// add search configuration to search parameters
+	p.CommonParams = s.CommonParams(s.Path, s.Recursive)
+
+	// send API call to search for artifacts in Artifactory
+	reader, err := cli.SearchFiles(p)
+	if err != nil {
+		return err
+	}
+
+	defer reader.Close()
+
+	if len(s.Aql) > 0 {
+		logger.Infof("Searching with AQL %s(%s)", aqlItemsFind, s.criteria())
+	}
+
+	var count int
+
+	// log every artifact found in Artifactory
+	for item := new(utils.ResultItem); reader.NextRecord(item) == nil; item = new(utils.ResultItem) {
+		logger.WithField("size", item.Size).Infof("  %s", item.GetItemRelativePath())
+
+		count++
+	}
+
+	err = reader.GetError()
+	if err != nil {
+		return err
+	}
+
+	withDuration(logger, start).WithField("success", count).Infof("Found %d artifact(s)", count)
+
+	return nil
+}
+
+// Validate verifies the Search is properly configured.
+func (s *Search) Validate() error {
+	logrus.Trace("validating search plugin configuration")
+
+	// verify the selection is valid
+	err := s.Selection.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid search selection provided: %w", err)
+	}
+
+	// verify path or aql is provided
+	return s.validatePath(searchAction, s.Path)
+}
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+// snapshotAql is an AQL query for large artifacts created in the last week in snapshot repositories.
+const snapshotAql = `items.find({"repo":{"$match":"*-snapshot"},"size":{"$gt":"104857600"},"created":{"$last":"7d"}})`
+
+func TestArtifactory_Search_Exec(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		search *Search
+		want   string
+	}{
+		{
+			name:   "path",
+			search: &Search{Path: "foo/bar"},
+			want:   `{"repo":"foo","path":".","name":"bar"}`,
+		},
+		{
+			name:   "aql",
+			search: &Search{Selection: Selection{Aql: snapshotAql}},
+			want:   `items.find({"repo":{"$match":"*-snapshot"},"size":{"$gt":"104857600"},"created":{"$last":"7d"}})`,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, queries := searchServer(t)
+
+			p := &Plugin{
+				Config: &Config{
+					Action:   "search",
+					URL:      s.URL,
+					Username: mock.Username,
+					Password: mock.Password,
+					Client: &Client{
+						Retries:            3,
+						RetryWaitMilliSecs: 1,
+					},
+				},
+				Search: test.search,
+			}
+
+			err := p.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = p.Exec()
+			if err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			if len(queries()) != 1 || !strings.Contains(queries()[0], test.want) {
+				t.Errorf("Exec searched with %v, want %s", queries(), test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Search_Exec_Error(t *testing.T) {
+	// setup types
+	config := &Config{
+		Action:   "search",
+		URL:      mock.InvalidArtifactoryServerURL,
+		Username: mock.Username,
+		Password: mock.Password,
+		Client: &Client{
+			Retries:            3,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	cli, err := config.New()
+	if err != nil {
+		t.Errorf("Unable to create Artifactory client: %v", err)
+	}
+
+	s := &Search{Path: "foo/bar"}
+
+	err = s.Exec(*cli)
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestArtifactory_Search_Validate(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	aqlFile := filepath.Join(dir, "query.aql")
+
+	err := os.WriteFile(aqlFile, []byte(snapshotAql+"\n"), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		search  *Search
+		wantErr bool
+	}{
+		{
+			name:   "path",
+			search: &Search{Path: "foo/bar"},
+		},
+		{
+			name:   "aql",
+			search: &Search{Selection: Selection{Aql: snapshotAql}},
+		},
+		{
+			name:   "aql criteria",
+			search: &Search{Selection: Selection{Aql: `{"repo":"libs-snapshot-local"}`}},
+		},
+		{
+			name:   "aql file",
+			search: &Search{Selection: Selection{AqlFile: aqlFile}},
+		},
+		{
+			name:    "no path or aql",
+			search:  &Search{},
+			wantErr: true,
+		},
+		{
+			name:    "path and aql",
+			search:  &Search{Path: "foo/bar", Selection: Selection{Aql: snapshotAql}},
+			wantErr: true,
+		},
+		{
+			name:    "aql and aql file",
+			search:  &Search{Selection: Selection{Aql: snapshotAql, AqlFile: aqlFile}},
+			wantErr: true,
+		},
+		{
+			name:    "missing aql file",
+			search:  &Search{Selection: Selection{AqlFile: filepath.Join(dir, "missing.aql")}},
+			wantErr: true,
+		},
+		{
+			name:    "write query",
+			search:  &Search{Selection: Selection{Aql: `items.find({"repo":"libs-snapshot-local"}).delete()`}},
+			wantErr: true,
+		},
+		{
+			name:    "other domain",
+			search:  &Search{Selection: Selection{Aql: `builds.find({"name":"app"})`}},
+			wantErr: true,
+		},
+		{
+			name:    "chained query",
+			search:  &Search{Selection: Selection{Aql: `items.find({"repo":"libs-snapshot-local"}).include("name")`}},
+			wantErr: true,
+		},
+		{
+			name:    "empty criteria",
+			search:  &Search{Selection: Selection{Aql: `items.find({})`}},
+			wantErr: true,
+		},
+		{
+			name:    "aql with include props",
+			search:  &Search{Selection: Selection{Aql: snapshotAql, IncludeProps: "qa.status=failed"}},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.search.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/sirupsen/logrus"
+)
+
+// aqlItemsFind is the AQL domain and function for searching artifacts.
+const aqlItemsFind = "items.find"
+
+// aqlWriteRe is a regular expression to match AQL
+// functions that modify, rather than search for, items.
+var aqlWriteRe = regexp.MustCompile(`(?i)\.\s*(delete|remove|update|set|put|post|move|copy)\s*\(`)
+
+// Selection represents the plugin configuration for narrowing
+// down the artifact(s) matching a path by properties and exclusions,
+// or for selecting the artifact(s) with an AQL query instead of a path.
+type Selection struct {
+	// Exclusions are path patterns for artifact(s) to leave out of the selection
+	Exclusions []string
+	// IncludeProps are properties the artifact(s) must have to be selected
+	IncludeProps string
+	// ExcludeProps are properties the artifact(s) must not have to be selected
+	ExcludeProps string
+	// Aql is an AQL items.find query for selecting the artifact(s) instead of a path
+	Aql string
+	// AqlFile is the path to a file in the workspace containing the AQL query
+	AqlFile string
+	// DryRun enables logging the AQL query and the selected artifact(s) at info level
+	DryRun bool
+}
+
+// CommonParams creates the search parameters for the artifact(s) matching the pattern,
+// or for the artifact(s) matching the AQL query when one is provided.
+func (s *Selection) CommonParams(pattern string, recursive bool) *utils.CommonParams {
+	if len(s.Aql) > 0 {
+		return &utils.CommonParams{
+			Aql:       utils.Aql{ItemsFind: s.criteria()},
+			Recursive: recursive,
+		}
+	}
+
+	return &utils.CommonParams{
+		Pattern:      pattern,
+		Recursive:    recursive,
+		Exclusions:   s.Exclusions,
+		Props:        s.IncludeProps,
+		ExcludeProps: s.ExcludeProps,
+	}
+}
+
+// criteria returns the search criteria of the AQL query without the items.find function.
+func (s *Selection) criteria() string {
+	criteria, _, _ := splitAql(s.Aql)
+
+	return criteria
+}
+
+// splitAql splits the AQL query into the search criteria within the items.find function
+// and the modifiers following it (e.g. .include(), .sort() or .limit()). The function
+// is closed by the parenthesis matching the one opening it, ignoring the parentheses
+// within the strings of the criteria. The query is returned as the criteria when it
+// does not use the items.find function, and false is returned when the function is
+// not closed.
+func splitAql(aql string) (string, string, bool) {
+	query := strings.TrimSpace(aql)
+
+	if !strings.HasPrefix(query, aqlItemsFind+"(") {
+		return query, "", true
+	}
+
+	body := query[len(aqlItemsFind)+1:]
+
+	var (
+		depth    = 1
+		inString bool
+		escaped  bool
+	)
+
+	for i, r := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && r == '\\':
+			escaped = true
+		case r == '"':
+			inString = !inString
+		case inString:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+
+			if depth == 0 {
+				return strings.TrimSpace(body[:i]), strings.TrimSpace(body[i+1:]), true
+			}
+		}
+	}
+
+	return strings.TrimSpace(body), "", false
+}
+
+// Validate verifies the Selection is properly configured.
+func (s *Selection) Validate() error {
+	logrus.Trace("validating selection configuration")
+
+	// check if the AQL query is provided in a file
+	if len(s.AqlFile) > 0 {
+		// verify the AQL query is only provided once
+		if len(s.Aql) > 0 {
+			return fmt.Errorf("aql and aql file are mutually exclusive")
+		}
+
+		data, err := os.ReadFile(s.AqlFile)
+		if err != nil {
+			return fmt.Errorf("unable to read aql file %s: %w", s.AqlFile, err)
+		}
+
+		s.Aql = string(data)
+	}
+
+	// check if the artifact(s) are selected with an AQL query
+	if len(s.Aql) > 0 {
+		return s.validateAql()
+	}
+
+	// verify every exclusion is a pattern
+	for _, exclusion := range s.Exclusions {
+		if len(strings.TrimSpace(exclusion)) == 0 {
+			return fmt.Errorf("empty exclusion provided")
+		}
+	}
+
+	// verify the include props are in the key=value;key=value form
+	_, err := utils.ParseProperties(s.IncludeProps)
+	if err != nil {
+		return fmt.Errorf("invalid include props %s provided: %w", s.IncludeProps, err)
+	}
+
+	// verify the exclude props are in the key=value;key=value form
+	_, err = utils.ParseProperties(s.ExcludeProps)
+	if err != nil {
+		return fmt.Errorf("invalid exclude props %s provided: %w", s.ExcludeProps, err)
+	}
+
+	return nil
+}
+
+// validateAql verifies the AQL query only searches for items.
+func (s *Selection) validateAql() error {
+	// verify the AQL query does not modify items
+	if aqlWriteRe.MatchString(s.Aql) {
+		return fmt.Errorf("aql query %s must not modify items", s.Aql)
+	}
+
+	// verify the AQL query only searches for items
+	query := strings.TrimSpace(s.Aql)
+	if !strings.HasPrefix(query, "{") && !strings.HasPrefix(query, aqlItemsFind+"(") {
+		return fmt.Errorf("aql query %s must use %s", s.Aql, aqlItemsFind)
+	}
+
+	_, modifiers, closed := splitAql(s.Aql)
+
+	// verify the items.find function is closed
+	if !closed {
+		return fmt.Errorf("aql query %s is missing the parenthesis closing %s", s.Aql, aqlItemsFind)
+	}
+
+	// verify the AQL query does not use modifiers, since the search criteria is sent to the client library
+	if len(modifiers) > 0 {
+		return fmt.Errorf("aql query %s must not use modifiers %s, only the %s search criteria is supported", s.Aql, modifiers, aqlItemsFind)
+	}
+
+	// verify the property and exclusion filters are not combined with the AQL query
+	if len(s.Exclusions) > 0 || len(s.IncludeProps) > 0 || len(s.ExcludeProps) > 0 {
+		return fmt.Errorf("exclusions, include props and exclude props are not supported with an aql query")
+	}
+
+	var criteria map[string]any
+
+	// verify the search criteria is a single JSON object
+	err := json.Unmarshal([]byte(s.criteria()), &criteria)
+	if err != nil {
+		return fmt.Errorf("invalid aql query %s provided: %w", s.Aql, err)
+	}
+
+	if len(criteria) == 0 {
+		return fmt.Errorf("no aql search criteria provided")
+	}
+
+	return nil
+}
+
+// validatePath verifies the artifact(s) are selected by either the path or an AQL query.
+func (s *Selection) validatePath(action, path string) error {
+	// verify path or aql is provided
+	if len(path) == 0 && len(s.Aql) == 0 {
+		return fmt.Errorf("no %s path or aql provided", action)
+	}
+
+	// verify path and aql are not both provided
+	if len(path) > 0 && len(s.Aql) > 0 {
+		return fmt.Errorf("%s path and aql are mutually exclusive", action)
+	}
+
+	return nil
+}
+
+// level returns the log level for the selected artifact(s).
+func (s *Selection) level() logrus.Level {
+	if s.DryRun {
+		return logrus.InfoLevel
+	}
+
+	return logrus.DebugLevel
+}
+
+// logSelection logs the AQL query and the path of every artifact in the
+// reader and rewinds the reader so it can be passed on to the action.
+func (s *Selection) logSelection(logger *logrus.Entry, reader *content.ContentReader) error {
+	level := s.level()
+
+	if !logger.Logger.IsLevelEnabled(level) {
+		return nil
+	}
+
+	defer reader.Reset()
+
+	if len(s.Aql) > 0 {
+		logger.Logf(level, "Selecting artifact(s) with AQL %s(%s)", aqlItemsFind, s.criteria())
+	}
+
+	var count int
+
+	for item := new(utils.ResultItem); reader.NextRecord(item) == nil; item = new(utils.ResultItem) {
+		logger.Logf(level, "  %s", item.GetItemRelativePath())
+
+		count++
+	}
+
+	err := reader.GetError()
+	if err != nil {
+		return err
+	}
+
+	logger.Logf(level, "Selected %d artifact(s)", count)
+
+	return nil
+}
+
+// searchSelection searches for and logs the artifact(s) matching
+// the parameters, for actions that search internally.
+func (s *Selection) searchSelection(cli artifactory.ArtifactoryServicesManager, logger *logrus.Entry, params *utils.CommonParams) error {
+	if !logger.Logger.IsLevelEnabled(s.level()) {
+		return nil
+	}
+
+	// create new search parameters
+	p := services.NewSearchParams()
+	p.CommonParams = params
+
+	// send API call to search for the artifact(s) in Artifactory
+	reader, err := cli.SearchFiles(p)
+	if err != nil {
+		return err
+	}
+
+	defer reader.Close()
+
+	return s.logSelection(logger, reader)
+}
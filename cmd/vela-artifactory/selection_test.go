@@ -0,0 +1,347 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+// searchServer returns a mock server that records the AQL queries sent to it.
+func searchServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/search/aql" {
+			body, _ := io.ReadAll(r.Body)
+
+			mu.Lock()
+			queries = append(queries, string(body))
+			mu.Unlock()
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return queries
+	}
+}
+
+func TestArtifactory_Selection_CommonParams(t *testing.T) {
+	// setup types
+	s := &Selection{
+		Exclusions:   []string{"*.md5", "libs-snapshot-local/keep/*"},
+		IncludeProps: "qa.status=failed",
+		ExcludeProps: "retain=true",
+	}
+
+	want := &utils.CommonParams{
+		Pattern:      "libs-snapshot-local/*",
+		Recursive:    true,
+		Exclusions:   []string{"*.md5", "libs-snapshot-local/keep/*"},
+		Props:        "qa.status=failed",
+		ExcludeProps: "retain=true",
+	}
+
+	got := s.CommonParams("libs-snapshot-local/*", true)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CommonParams is %v, want %v", got, want)
+	}
+}
+
+func TestArtifactory_Selection_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		s       *Selection
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			s:    &Selection{},
+		},
+		{
+			name: "valid",
+			s:    &Selection{Exclusions: []string{"*.md5"}, IncludeProps: "qa.status=failed;os=linux,darwin", ExcludeProps: "retain=true"},
+		},
+		{
+			name:    "empty exclusion",
+			s:       &Selection{Exclusions: []string{"*.md5", " "}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid include props",
+			s:       &Selection{IncludeProps: "qa.status"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid exclude props",
+			s:       &Selection{ExcludeProps: "=true"},
+			wantErr: true,
+		},
+		{
+			name: "aql with parentheses in criteria",
+			s:    &Selection{Aql: `items.find({"name":{"$match":"app(1).jar"}})`},
+		},
+		{
+			name:    "aql include",
+			s:       &Selection{Aql: `items.find({"repo":"foo"}).include("name","repo")`},
+			wantErr: true,
+		},
+		{
+			name:    "aql sort",
+			s:       &Selection{Aql: `items.find({"repo":"foo"}).sort({"$desc":["created"]})`},
+			wantErr: true,
+		},
+		{
+			name:    "aql limit",
+			s:       &Selection{Aql: `items.find({"repo":"foo"}).limit(10)`},
+			wantErr: true,
+		},
+		{
+			name:    "aql not closed",
+			s:       &Selection{Aql: `items.find({"repo":"foo"}`},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.s.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Selection_criteria(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name          string
+		aql           string
+		want          string
+		wantModifiers string
+		wantClosed    bool
+	}{
+		{
+			name:       "criteria",
+			aql:        `{"repo":"foo"}`,
+			want:       `{"repo":"foo"}`,
+			wantClosed: true,
+		},
+		{
+			name:       "items.find",
+			aql:        ` items.find( {"repo":"foo"} ) `,
+			want:       `{"repo":"foo"}`,
+			wantClosed: true,
+		},
+		{
+			name:       "parentheses in strings",
+			aql:        `items.find({"name":{"$match":"app(1)).jar"},"path":"a\"(b"})`,
+			want:       `{"name":{"$match":"app(1)).jar"},"path":"a\"(b"}`,
+			wantClosed: true,
+		},
+		{
+			name:          "modifiers",
+			aql:           `items.find({"repo":"foo"}).include("name").sort({"$asc":["name"]}).limit(10)`,
+			want:          `{"repo":"foo"}`,
+			wantModifiers: `.include("name").sort({"$asc":["name"]}).limit(10)`,
+			wantClosed:    true,
+		},
+		{
+			name: "not closed",
+			aql:  `items.find({"repo":"foo"}`,
+			want: `{"repo":"foo"}`,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, modifiers, closed := splitAql(test.aql)
+
+			if got != test.want {
+				t.Errorf("splitAql criteria is %s, want %s", got, test.want)
+			}
+
+			if modifiers != test.wantModifiers {
+				t.Errorf("splitAql modifiers is %s, want %s", modifiers, test.wantModifiers)
+			}
+
+			if closed != test.wantClosed {
+				t.Errorf("splitAql closed is %v, want %v", closed, test.wantClosed)
+			}
+
+			if got := (&Selection{Aql: test.aql}).criteria(); got != test.want {
+				t.Errorf("criteria is %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Selection_Exec(t *testing.T) {
+	// setup types
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.DebugLevel)
+
+	t.Cleanup(func() { logrus.SetLevel(level) })
+
+	selection := Selection{
+		Exclusions:   []string{"*.md5"},
+		IncludeProps: "qa.status=failed",
+		ExcludeProps: "retain=true",
+	}
+
+	// setup tests
+	tests := []struct {
+		name   string
+		action string
+		plugin *Plugin
+	}{
+		{
+			name:   "copy",
+			action: copyAction,
+			plugin: &Plugin{Copy: &Copy{Selection: selection, Path: "foo/bar", Target: "bar/foo"}},
+		},
+		{
+			name:   "move",
+			action: moveAction,
+			plugin: &Plugin{Move: &Move{Selection: selection, Path: "foo/bar", Target: "bar/foo"}},
+		},
+		{
+			name:   "delete",
+			action: deleteAction,
+			plugin: &Plugin{Delete: &Delete{Selection: selection, Path: "foo/bar"}},
+		},
+		{
+			name:   "set-prop",
+			action: setPropAction,
+			plugin: &Plugin{SetProp: &SetProp{Selection: selection, Path: "foo/bar", Props: []*Prop{{Name: "foo", Value: "bar"}}}},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, queries := searchServer(t)
+
+			test.plugin.Config = &Config{
+				Action:   test.action,
+				URL:      s.URL,
+				Username: mock.Username,
+				Password: mock.Password,
+				Client: &Client{
+					Retries:            3,
+					RetryWaitMilliSecs: 1,
+				},
+			}
+
+			err := test.plugin.Exec()
+			if err != nil {
+				t.Errorf("Exec returned err: %v", err)
+			}
+
+			if len(queries()) == 0 {
+				t.Fatalf("Exec did not search for artifacts")
+			}
+
+			for _, query := range queries() {
+				for _, want := range []string{`"@qa.status":"failed"`, `"@retain":{"$ne":"true"}`, `"name":{"$nmatch":"*.md5"}`} {
+					if !strings.Contains(query, want) {
+						t.Errorf("Exec searched with %s, want %s", query, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestArtifactory_Selection_Exec_AqlDryRun(t *testing.T) {
+	// setup types
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost || r.Method == http.MethodDelete {
+			body, _ := io.ReadAll(r.Body)
+
+			mu.Lock()
+			requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+			mu.Unlock()
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "delete",
+			DryRun:   true,
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Delete: &Delete{
+			Selection: Selection{Aql: snapshotAql, DryRun: true},
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	if len(requests) != 1 || !strings.Contains(requests[0], `POST /api/search/aql items.find({"repo":{"$match":"*-snapshot"}`) {
+		t.Errorf("Exec sent requests %v, want a single AQL search", requests)
+	}
+}
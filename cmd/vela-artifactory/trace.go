@@ -0,0 +1,328 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/vela-artifactory/version"
+)
+
+// traceBodyLimit is the maximum number of bytes recorded for a request or response body.
+const traceBodyLimit = 64 * 1024
+
+// traceSecretHeaders represents the HTTP headers that are always redacted in a trace.
+var traceSecretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+	"Set-Cookie":          true,
+	"X-Jfrog-Art-Api":     true,
+}
+
+// traceRecorder records every HTTP request and response sent to
+// Artifactory so they can be written to a HAR-like JSON file.
+//
+// http://www.softwareishard.com/blog/har-12-spec/
+type traceRecorder struct {
+	mu       sync.Mutex
+	entries  []*traceEntry
+	redactor *redactor
+}
+
+// traceEntry represents a single recorded request and response.
+type traceEntry struct {
+	started  time.Time
+	duration time.Duration
+	request  *http.Request
+	response *http.Response
+	err      error
+
+	requestBody  *traceBody
+	responseBody *traceBody
+}
+
+// traceBody captures up to traceBodyLimit bytes of a body as it is read.
+type traceBody struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	size      int64
+	truncated bool
+}
+
+// Write captures the bytes read from a body.
+func (b *traceBody) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := len(p)
+	b.size += int64(n)
+
+	remaining := traceBodyLimit - b.buf.Len()
+	if remaining < n {
+		b.truncated = true
+
+		p = p[:max(remaining, 0)]
+	}
+
+	b.buf.Write(p)
+
+	// always report the full length to avoid interrupting the body
+	return n, nil
+}
+
+// text returns the captured body in a form suitable for the trace.
+func (b *traceBody) text() (string, int64) {
+	if b == nil {
+		return "", 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	data := b.buf.Bytes()
+
+	if !utf8.Valid(data) {
+		return "[binary content omitted]", b.size
+	}
+
+	text := string(data)
+
+	if b.truncated {
+		text += "\n[truncated]"
+	}
+
+	return text, b.size
+}
+
+// traceReadCloser captures a body as it is read by the client or server.
+type traceReadCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+// Close closes the underlying body.
+func (t *traceReadCloser) Close() error {
+	return t.closer.Close()
+}
+
+// newTraceRecorder creates a recorder that redacts secrets from the trace.
+func newTraceRecorder(r *redactor) *traceRecorder {
+	return &traceRecorder{redactor: r}
+}
+
+// Transport wraps the base transport to record every request and response.
+func (t *traceRecorder) Transport(base http.RoundTripper) http.RoundTripper {
+	return &traceTransport{base: base, recorder: t}
+}
+
+// traceTransport is an http.RoundTripper that records requests and responses.
+type traceTransport struct {
+	base     http.RoundTripper
+	recorder *traceRecorder
+}
+
+// RoundTrip records the request and response for the trace.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := &traceEntry{
+		started: time.Now(),
+		request: req,
+	}
+
+	// capture the request body as it is sent
+	if req.Body != nil && req.Body != http.NoBody {
+		entry.requestBody = new(traceBody)
+
+		clone := req.Clone(req.Context())
+		clone.Body = &traceReadCloser{
+			Reader: io.TeeReader(req.Body, entry.requestBody),
+			closer: req.Body,
+		}
+
+		req = clone
+	}
+
+	resp, err := t.base.RoundTrip(req)
+
+	entry.duration = time.Since(entry.started)
+	entry.response = resp
+	entry.err = err
+
+	// capture the response body as it is read
+	if resp != nil && resp.Body != nil {
+		entry.responseBody = new(traceBody)
+
+		resp.Body = &traceReadCloser{
+			Reader: io.TeeReader(resp.Body, entry.responseBody),
+			closer: resp.Body,
+		}
+	}
+
+	t.recorder.mu.Lock()
+	t.recorder.entries = append(t.recorder.entries, entry)
+	t.recorder.mu.Unlock()
+
+	return resp, err
+}
+
+// harHeader represents a header or query parameter in a HAR file.
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harContent represents a request or response body in a HAR file.
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+// harRequest represents a request in a HAR file.
+type harRequest struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Headers  []harHeader `json:"headers"`
+	PostData *harContent `json:"postData,omitempty"`
+}
+
+// harResponse represents a response in a HAR file.
+type harResponse struct {
+	Status     int         `json:"status"`
+	StatusText string      `json:"statusText"`
+	Headers    []harHeader `json:"headers"`
+	Content    harContent  `json:"content"`
+}
+
+// harEntry represents a request and response pair in a HAR file.
+type harEntry struct {
+	StartedDateTime string       `json:"startedDateTime"`
+	Time            int64        `json:"time"`
+	Request         harRequest   `json:"request"`
+	Response        *harResponse `json:"response,omitempty"`
+	Error           string       `json:"_error,omitempty"`
+}
+
+// harLog represents the contents of a HAR file.
+type harLog struct {
+	Log struct {
+		Version string `json:"version"`
+		Creator struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+// headers returns the redacted headers sorted by name.
+func (t *traceRecorder) headers(h http.Header) []harHeader {
+	headers := []harHeader{}
+
+	for name, values := range h {
+		for _, value := range values {
+			if traceSecretHeaders[http.CanonicalHeaderKey(name)] {
+				value = redacted
+			}
+
+			headers = append(headers, harHeader{Name: name, Value: t.redactor.Redact(value)})
+		}
+	}
+
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+
+	return headers
+}
+
+// HAR returns the recorded requests and responses in HAR format.
+func (t *traceRecorder) HAR() *harLog {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	har := new(harLog)
+	har.Log.Version = "1.2"
+	har.Log.Creator.Name = "vela-artifactory"
+	har.Log.Creator.Version = version.New().Semantic()
+	har.Log.Entries = []harEntry{}
+
+	for _, e := range t.entries {
+		entry := harEntry{
+			StartedDateTime: e.started.UTC().Format(time.RFC3339Nano),
+			Time:            e.duration.Milliseconds(),
+			Request: harRequest{
+				Method:  e.request.Method,
+				URL:     t.redactor.Redact(e.request.URL.String()),
+				Headers: t.headers(e.request.Header),
+			},
+		}
+
+		if e.requestBody != nil {
+			text, size := e.requestBody.text()
+
+			entry.Request.PostData = &harContent{
+				Size:     size,
+				MimeType: e.request.Header.Get("Content-Type"),
+				Text:     t.redactor.Redact(text),
+			}
+		}
+
+		if e.response != nil {
+			text, size := e.responseBody.text()
+
+			entry.Response = &harResponse{
+				Status:     e.response.StatusCode,
+				StatusText: strings.TrimSpace(strings.TrimPrefix(e.response.Status, strconv.Itoa(e.response.StatusCode))),
+				Headers:    t.headers(e.response.Header),
+				Content: harContent{
+					Size:     size,
+					MimeType: e.response.Header.Get("Content-Type"),
+					Text:     t.redactor.Redact(text),
+				},
+			}
+
+			// prefer the reason phrase sent by Artifactory when available
+			if len(entry.Response.StatusText) == 0 {
+				entry.Response.StatusText = http.StatusText(e.response.StatusCode)
+			}
+		}
+
+		if e.err != nil {
+			entry.Error = t.redactor.Redact(e.err.Error())
+		}
+
+		har.Log.Entries = append(har.Log.Entries, entry)
+	}
+
+	return har
+}
+
+// Write saves the recorded requests and responses to the file in HAR format.
+func (t *traceRecorder) Write(path string) error {
+	logrus.Tracef("writing HTTP trace to %s", path)
+
+	data, err := json.MarshalIndent(t.HAR(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// create the parent directories for the trace file
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
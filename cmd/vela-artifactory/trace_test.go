@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_Config_WriteTrace(t *testing.T) {
+	// setup types
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	file := filepath.Join(t.TempDir(), "trace", "artifactory.har")
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "copy",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+				TraceFile:          file,
+			},
+		},
+		Copy: &Copy{
+			Path:   "foo/bar",
+			Target: "bar/foo",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("unable to read trace file: %v", err)
+	}
+
+	if strings.Contains(string(data), mock.Password) {
+		t.Errorf("trace file contains secret")
+	}
+
+	har := new(harLog)
+
+	err = json.Unmarshal(data, har)
+	if err != nil {
+		t.Fatalf("unable to parse trace file: %v", err)
+	}
+
+	// search and copy requests are sent for the copy action
+	if len(har.Log.Entries) != 2 {
+		t.Fatalf("trace has %d entries, want 2", len(har.Log.Entries))
+	}
+
+	search := har.Log.Entries[0]
+
+	if search.Request.Method != "POST" || !strings.HasSuffix(search.Request.URL, "/api/search/aql") {
+		t.Errorf("trace entry is %s %s, want POST /api/search/aql", search.Request.Method, search.Request.URL)
+	}
+
+	if search.Request.PostData == nil || !strings.Contains(search.Request.PostData.Text, "items.find") {
+		t.Errorf("trace entry did not record request body")
+	}
+
+	if search.Response == nil || search.Response.Status != 200 || search.Response.StatusText != "OK" {
+		t.Errorf("trace entry did not record response status")
+	}
+
+	if search.Response != nil && !strings.Contains(search.Response.Content.Text, "results") {
+		t.Errorf("trace entry did not record response body")
+	}
+
+	for _, header := range search.Request.Headers {
+		if header.Name == "Authorization" && header.Value != redacted {
+			t.Errorf("trace entry Authorization header is %s, want %s", header.Value, redacted)
+		}
+	}
+}
+
+func TestArtifactory_Config_WriteTrace_Disabled(t *testing.T) {
+	// setup types
+	c := &Config{
+		Client: &Client{},
+	}
+
+	// should not panic without a recorder
+	c.WriteTrace()
+}
+
+func TestArtifactory_traceBody_Truncated(t *testing.T) {
+	// setup types
+	b := new(traceBody)
+
+	n, err := b.Write([]byte(strings.Repeat("a", traceBodyLimit+10)))
+	if err != nil {
+		t.Errorf("Write returned err: %v", err)
+	}
+
+	if n != traceBodyLimit+10 {
+		t.Errorf("Write is %d, want %d", n, traceBodyLimit+10)
+	}
+
+	text, size := b.text()
+
+	if size != int64(traceBodyLimit+10) {
+		t.Errorf("text size is %d, want %d", size, traceBodyLimit+10)
+	}
+
+	if !strings.HasSuffix(text, "[truncated]") {
+		t.Errorf("text should be truncated")
+	}
+}
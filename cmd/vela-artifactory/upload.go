@@ -4,7 +4,9 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
@@ -28,48 +30,143 @@ type Upload struct {
 	Path string
 	// build props
 	BuildProps string
-	// list of files to upload
+	// list of files to upload, either plain patterns or structured entries
 	Sources []string
+	// template for the target path of each file, relative to the path
+	Layout string
+	// regular expression for capturing layout tokens from the name of each file
+	LayoutPattern string
+	// enables previewing the target path of each file without uploading
+	DryRun bool
+	// patterns for the files matching the sources to exclude from the upload
+	Exclusions []string
+	// path to a file in the workspace listing the files to upload
+	Spec string
+	// files to upload parsed from the spec
+	Files []*UploadSource
 }
 
 // Exec formats and runs the commands for uploading artifacts in Artifactory.
 func (u *Upload) Exec(cli artifactory.ArtifactoryServicesManager) error {
 	logrus.Trace("running upload with provided configuration")
 
+	logger := actionLogger(uploadAction, u.Path)
+
+	// upload the files listed in the spec
+	if len(u.Spec) > 0 {
+		return u.execSpec(cli, logger)
+	}
+
+	// upload each file to the target path rendered from the layout
+	if len(u.Layout) > 0 {
+		return u.execLayout(cli, logger)
+	}
+
+	sources, err := u.entries()
+	if err != nil {
+		return err
+	}
+
 	// very simple check that doesn't account for:
 	// - regex in sources, in which case it's possible that one source could be multiple files
 	// - defining a singular source twice
-	if len(u.Sources) > 1 && !strings.HasSuffix(u.Path, "/") {
+	if len(sources) > 1 && !strings.HasSuffix(u.Path, "/") {
 		logrus.Warn("when uploading multiple sources, path should be a directory")
 	}
 
+	build := newBuildMetadata()
+
 	// iterate through all sources
-	for _, source := range u.Sources {
+	for _, source := range sources {
+		start := time.Now()
+
+		// create new upload parameters for the source
+		p, err := u.sourceParams(source, build)
+		if err != nil {
+			return err
+		}
+
+		// list the files selected for the source
+		if u.DryRun {
+			err = logPlan(logger, p)
+			if err != nil {
+				return err
+			}
+		}
+
+		// send API call to upload artifacts in Artifactory
+		totalUploaded, totalFailed, err := cli.UploadFiles(artifactory.UploadServiceOptions{FailFast: true}, p)
+		if err != nil {
+			return err
+		}
+
+		if totalFailed > 0 {
+			return fmt.Errorf("unable to upload %d artifact(s)", totalFailed)
+		}
+
+		withDuration(logger, start).WithFields(logrus.Fields{
+			"artifact": source.Pattern,
+			"success":  totalUploaded,
+			"failed":   totalFailed,
+		}).Infof("Uploaded %d artifact(s) from %s to %s", totalUploaded, source.Pattern, u.target(source))
+	}
+
+	return nil
+}
+
+// execLayout uploads each file matching the sources to the target path rendered from the layout.
+func (u *Upload) execLayout(cli artifactory.ArtifactoryServicesManager, logger *logrus.Entry) error {
+	start := time.Now()
+
+	targets, err := u.LayoutTargets(newBuildMetadata())
+	if err != nil {
+		return err
+	}
+
+	params := make([]services.UploadParams, 0, len(targets))
+
+	for _, target := range targets {
+		entry := logger.WithField("artifact", target.Source)
+
+		if u.DryRun {
+			entry.Infof("  [dry run] %s -> %s", target.Source, target.Target)
+		} else {
+			entry.Infof("  [layout] %s -> %s", target.Source, target.Target)
+		}
+
 		// create new upload parameters
 		p := services.NewUploadParams()
 
 		// apply build props
 		p.BuildProps = u.BuildProps
 
-		// add upload configuration to upload parameters
+		// add file configuration to upload parameters
 		p.CommonParams = &utils.CommonParams{
-			IncludeDirs: u.IncludeDirs,
-			Pattern:     source,
-			Recursive:   u.Recursive,
-			Regexp:      u.Regexp,
-			Target:      u.Path,
+			Pattern: target.Source,
+			Target:  target.Target,
 		}
 
 		// upload to exact target path
-		p.Flat = u.Flat
+		p.Flat = true
 
-		// send API call to upload artifacts in Artifactory
-		_, totalFailed, err := cli.UploadFiles(artifactory.UploadServiceOptions{FailFast: true}, p)
-		if totalFailed > 0 || err != nil {
-			return err
-		}
+		params = append(params, p)
+	}
+
+	// send API call to upload artifacts in Artifactory
+	totalUploaded, totalFailed, err := cli.UploadFiles(artifactory.UploadServiceOptions{FailFast: true}, params...)
+	if err != nil {
+		return err
+	}
+
+	if totalFailed > 0 {
+		return fmt.Errorf("unable to upload %d artifact(s)", totalFailed)
 	}
 
+	withDuration(logger, start).WithFields(logrus.Fields{
+		"success": totalUploaded,
+		"failed":  totalFailed,
+	}).Infof("Uploaded %d artifact(s) using layout %s", totalUploaded, u.Layout)
+
 	return nil
 }
 
@@ -77,14 +174,26 @@ func (u *Upload) Exec(cli artifactory.ArtifactoryServicesManager) error {
 func (u *Upload) Validate() error {
 	logrus.Trace("validating upload plugin configuration")
 
-	// verify path is provided
-	if len(u.Path) == 0 {
-		return fmt.Errorf("no upload path provided")
+	// verify exclusions are not empty
+	if slices.Contains(u.Exclusions, "") {
+		return fmt.Errorf("empty upload exclusion provided")
+	}
+
+	// verify the spec when the files to upload are listed in a spec
+	if len(u.Spec) > 0 {
+		return u.validateSpec()
+	}
+
+	// verify sources are provided with a target or path
+	err := u.validateSources()
+	if err != nil {
+		return err
 	}
 
-	// verify sources are provided
-	if len(u.Sources) == 0 {
-		return fmt.Errorf("no upload sources provided")
+	// verify the layout resolves a target path for every file
+	err = u.validateLayout()
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/sirupsen/logrus"
+)
+
+// uploadPlan represents the local files selected by the upload parameters.
+type uploadPlan struct {
+	// Files are the paths to the files to upload
+	Files []string
+	// Excluded are the paths to the files skipped by the exclusions
+	Excluded []string
+	// Matched are the exclusions matching at least one of the files
+	Matched []string
+}
+
+// matchExclusions returns the exclusions matching the path to the file, converting
+// each exclusion to a regular expression the same way as the client library.
+func matchExclusions(file string, exclusions []string, patternType clientutils.PatternType, recursive bool) ([]string, error) {
+	var matched []string
+
+	for _, exclusion := range exclusions {
+		pattern := fspatterns.PrepareExcludePathPattern([]string{exclusion}, patternType, recursive)
+
+		ok, err := regexp.MatchString(pattern, file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upload exclusion %s provided: %w", exclusion, err)
+		}
+
+		if ok {
+			matched = append(matched, exclusion)
+		}
+	}
+
+	return matched, nil
+}
+
+// planUpload returns the local files selected by the upload parameters, collected the
+// same way as the client library, along with the files skipped by the exclusions.
+func planUpload(p services.UploadParams) (*uploadPlan, error) {
+	pattern := clientutils.ReplaceTildeWithUserHome(p.GetPattern())
+	target := strings.TrimPrefix(p.GetTarget(), "/")
+
+	root, err := fspatterns.GetRootPath(pattern, target, "", p.GetPatternType(), false)
+	if err != nil {
+		return nil, fmt.Errorf("no files found for upload source %s: %w", p.GetPattern(), err)
+	}
+
+	isDir, err := fileutils.IsDirExists(root, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// the client library uploads a single file without applying the exclusions
+	if !isDir {
+		return &uploadPlan{Files: []string{root}}, nil
+	}
+
+	// escape the parentheses without a placeholder in the target
+	regex := clientutils.ConvertLocalPatternToRegexp(pattern, p.GetPatternType())
+	if !p.Regexp {
+		regex = clientutils.AddEscapingParentheses(regex, target, "")
+	}
+
+	re, err := clientutils.GetRegExp(regex)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := fspatterns.ListFiles(root, p.IsRecursive(), p.IsIncludeDirs(), false, false, "")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := new(uploadPlan)
+
+	for _, path := range paths {
+		matches, _, err := fspatterns.SearchPatterns(path, false, p.IsIncludeDirs(), re)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matches) == 0 {
+			continue
+		}
+
+		matched, err := matchExclusions(path, p.GetExclusions(), p.GetPatternType(), p.IsRecursive())
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matched) == 0 {
+			plan.Files = append(plan.Files, path)
+
+			continue
+		}
+
+		plan.Excluded = append(plan.Excluded, path)
+
+		for _, exclusion := range matched {
+			if !slices.Contains(plan.Matched, exclusion) {
+				plan.Matched = append(plan.Matched, exclusion)
+			}
+		}
+	}
+
+	slices.Sort(plan.Files)
+	slices.Sort(plan.Excluded)
+
+	return plan, nil
+}
+
+// validateExclusions verifies every exclusion matches at least one of the files selected by
+// the upload parameters. The exclusions of the upload apply to the files of every parameter,
+// while the other exclusions of a parameter only apply to the files of that parameter.
+func (u *Upload) validateExclusions(params []services.UploadParams) error {
+	var (
+		matched   []string
+		unmatched []string
+	)
+
+	for _, p := range params {
+		// the files are only listed when there are exclusions to apply
+		if len(p.GetExclusions()) == 0 {
+			continue
+		}
+
+		plan, err := planUpload(p)
+		if err != nil {
+			return err
+		}
+
+		matched = append(matched, plan.Matched...)
+
+		for _, exclusion := range p.GetExclusions() {
+			if slices.Contains(u.Exclusions, exclusion) || slices.Contains(plan.Matched, exclusion) {
+				continue
+			}
+
+			unmatched = append(unmatched, fmt.Sprintf("%s (source %s)", exclusion, p.GetPattern()))
+		}
+	}
+
+	for _, exclusion := range u.Exclusions {
+		if !slices.Contains(matched, exclusion) {
+			unmatched = append(unmatched, exclusion)
+		}
+	}
+
+	if len(unmatched) > 0 {
+		return fmt.Errorf("upload exclusions do not match any files: %s", strings.Join(unmatched, ", "))
+	}
+
+	return nil
+}
+
+// logPlan logs the files selected by the upload parameters and the files skipped by the exclusions.
+func logPlan(logger *logrus.Entry, p services.UploadParams) error {
+	plan, err := planUpload(p)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range plan.Files {
+		logger.WithField("artifact", file).Infof("  [dry run] %s -> %s", file, p.GetTarget())
+	}
+
+	for _, file := range plan.Excluded {
+		logger.WithField("artifact", file).Infof("  [excluded] %s", file)
+	}
+
+	return nil
+}
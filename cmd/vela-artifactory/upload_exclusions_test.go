@@ -0,0 +1,346 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+// writeDist writes the files for a web application build to a temporary directory.
+func writeDist(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for _, file := range []string{
+		"dist/app.js",
+		"dist/app.js.map",
+		"dist/lib/util.js",
+		"dist/lib/util.js.map",
+		"dist/node_modules/left-pad/index.js",
+	} {
+		path := filepath.Join(dir, file)
+
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatalf("unable to create directory: %v", err)
+		}
+
+		err = os.WriteFile(path, []byte(file), 0o600)
+		if err != nil {
+			t.Fatalf("unable to write file: %v", err)
+		}
+	}
+
+	return dir
+}
+
+func TestArtifactory_planUpload(t *testing.T) {
+	// setup types
+	dir := writeDist(t)
+
+	// setup tests
+	tests := []struct {
+		name         string
+		pattern      string
+		regexp       bool
+		exclusions   []string
+		wantFiles    []string
+		wantExcluded []string
+		wantMatched  []string
+	}{
+		{
+			name:      "no exclusions",
+			pattern:   "dist/*.js",
+			wantFiles: []string{"dist/app.js", "dist/lib/util.js", "dist/node_modules/left-pad/index.js"},
+		},
+		{
+			name:         "glob",
+			pattern:      "dist/*",
+			exclusions:   []string{"*.map", "*/node_modules/*", "*.css"},
+			wantFiles:    []string{"dist/app.js", "dist/lib/util.js"},
+			wantExcluded: []string{"dist/app.js.map", "dist/lib/util.js.map", "dist/node_modules/left-pad/index.js"},
+			wantMatched:  []string{"*.map", "*/node_modules/*"},
+		},
+		{
+			name:         "regexp",
+			pattern:      "dist/(.*)",
+			regexp:       true,
+			exclusions:   []string{`.*\.map$`},
+			wantFiles:    []string{"dist/app.js", "dist/lib/util.js", "dist/node_modules/left-pad/index.js"},
+			wantExcluded: []string{"dist/app.js.map", "dist/lib/util.js.map"},
+			wantMatched:  []string{`.*\.map$`},
+		},
+		{
+			name:       "single file",
+			pattern:    "dist/app.js.map",
+			exclusions: []string{"*.map"},
+			wantFiles:  []string{"dist/app.js.map"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := services.NewUploadParams()
+			p.CommonParams = &utils.CommonParams{
+				Exclusions: test.exclusions,
+				Pattern:    filepath.Join(dir, test.pattern),
+				Recursive:  true,
+				Regexp:     test.regexp,
+				Target:     "foo/bar/",
+			}
+
+			got, err := planUpload(p)
+			if err != nil {
+				t.Fatalf("planUpload returned err: %v", err)
+			}
+
+			// the files are listed with the absolute path to the temporary directory
+			rel := func(files []string) []string {
+				var paths []string
+
+				for _, file := range files {
+					paths = append(paths, filepath.ToSlash(strings.TrimPrefix(file, dir+string(filepath.Separator))))
+				}
+
+				return paths
+			}
+
+			if !reflect.DeepEqual(rel(got.Files), test.wantFiles) {
+				t.Errorf("planUpload files is %v, want %v", rel(got.Files), test.wantFiles)
+			}
+
+			if !reflect.DeepEqual(rel(got.Excluded), test.wantExcluded) {
+				t.Errorf("planUpload excluded is %v, want %v", rel(got.Excluded), test.wantExcluded)
+			}
+
+			if !reflect.DeepEqual(got.Matched, test.wantMatched) {
+				t.Errorf("planUpload matched is %v, want %v", got.Matched, test.wantMatched)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Plugin_Exec_UploadWithExclusions(t *testing.T) {
+	// setup types
+	dir := writeDist(t)
+
+	var (
+		mu       sync.Mutex
+		uploaded []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		mu.Lock()
+		uploaded = append(uploaded, r.URL.Path)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "upload",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Upload: &Upload{
+			Flat:       true,
+			Recursive:  true,
+			Path:       "foo/bar/",
+			Sources:    []string{filepath.Join(dir, "dist/*")},
+			Exclusions: []string{"*.map", "*/node_modules/*"},
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	sort.Strings(uploaded)
+
+	want := []string{"/foo/bar/app.js", "/foo/bar/util.js"}
+
+	if !reflect.DeepEqual(uploaded, want) {
+		t.Errorf("Exec uploaded %v, want %v", uploaded, want)
+	}
+}
+
+func TestArtifactory_Plugin_Exec_UploadWithExclusions_DryRun(t *testing.T) {
+	// setup types
+	dir := writeDist(t)
+
+	s := httptest.NewServer(mock.Handlers())
+	defer s.Close()
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "upload",
+			DryRun:   true,
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Upload: &Upload{
+			DryRun:     true,
+			Recursive:  true,
+			Path:       "foo/bar/",
+			Sources:    []string{filepath.Join(dir, "dist/*")},
+			Exclusions: []string{"*.map", "*/node_modules/*"},
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	var got []string
+
+	for _, entry := range hook.AllEntries() {
+		if strings.HasPrefix(entry.Message, "  [") {
+			got = append(got, strings.ReplaceAll(entry.Message, dir+string(filepath.Separator), ""))
+		}
+	}
+
+	want := []string{
+		"  [dry run] dist/app.js -> foo/bar/",
+		"  [dry run] dist/lib/util.js -> foo/bar/",
+		"  [excluded] dist/app.js.map",
+		"  [excluded] dist/lib/util.js.map",
+		"  [excluded] dist/node_modules/left-pad/index.js",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Exec logged %v, want %v", got, want)
+	}
+}
+
+func TestArtifactory_Upload_Validate_Exclusions(t *testing.T) {
+	// setup types
+	dir := writeDist(t)
+	dist := filepath.Join(dir, "dist/*")
+
+	spec := writeSpec(t, "upload.yml", `
+files:
+  - source: `+dist+`
+    target: foo/bar/
+    recursive: true
+`)
+
+	// setup tests
+	tests := []struct {
+		name    string
+		upload  *Upload
+		wantErr string
+	}{
+		{
+			name:   "matched",
+			upload: &Upload{Path: "foo/bar/", Recursive: true, Sources: []string{dist}, Exclusions: []string{"*.map"}},
+		},
+		{
+			name:    "unmatched",
+			upload:  &Upload{Path: "foo/bar/", Recursive: true, Sources: []string{dist}, Exclusions: []string{"*.map", "*.css"}},
+			wantErr: "upload exclusions do not match any files: *.css",
+		},
+		{
+			name:    "empty",
+			upload:  &Upload{Path: "foo/bar/", Sources: []string{dist}, Exclusions: []string{""}},
+			wantErr: "empty upload exclusion provided",
+		},
+		{
+			name: "unmatched source",
+			upload: &Upload{
+				Path:      "foo/bar/",
+				Recursive: true,
+				Sources:   []string{`[{"pattern":"` + dist + `"`, `"exclusions":["*.css"]}]`},
+			},
+			wantErr: "upload exclusions do not match any files: *.css (source " + dist + ")",
+		},
+		{
+			name: "matched by another source",
+			upload: &Upload{
+				Path:       "foo/bar/",
+				Recursive:  true,
+				Sources:    []string{filepath.Join(dir, "dist/*.map"), filepath.Join(dir, "dist/node_modules/*")},
+				Exclusions: []string{"*.map", "*/node_modules/*"},
+			},
+		},
+		{
+			name:   "layout",
+			upload: &Upload{Path: "foo/bar/", Recursive: true, Sources: []string{dist}, Layout: "{{ .Dir }}/{{ .File }}", Exclusions: []string{"*.map"}},
+		},
+		{
+			name:    "unmatched layout",
+			upload:  &Upload{Path: "foo/bar/", Recursive: true, Sources: []string{dist}, Layout: "{{ .Dir }}/{{ .File }}", Exclusions: []string{"*.css"}},
+			wantErr: "upload exclusions do not match any files: *.css",
+		},
+		{
+			name:   "spec",
+			upload: &Upload{Spec: spec, Exclusions: []string{"*/node_modules/*"}},
+		},
+		{
+			name:    "unmatched spec",
+			upload:  &Upload{Spec: spec, Exclusions: []string{"*.css"}},
+			wantErr: "upload exclusions do not match any files: *.css",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.upload.Validate()
+
+			if len(test.wantErr) == 0 && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+
+			if len(test.wantErr) > 0 && (err == nil || err.Error() != test.wantErr) {
+				t.Errorf("Validate returned err %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+)
+
+// compoundExts are the file extensions made up of multiple parts.
+var compoundExts = []string{".tar.gz", ".tar.bz2", ".tar.xz", ".tar.zst"}
+
+// layoutTemplateData represents the values available to upload layout templates.
+type layoutTemplateData struct {
+	*BuildMetadata
+
+	// File is the name of the file being uploaded
+	File string
+	// Name is the name of the file without its extension
+	Name string
+	// Ext is the extension of the file without the leading dot
+	Ext string
+	// Dir is the directory of the file relative to the source directory
+	Dir string
+	// Match are the capture groups of the layout pattern matched against the file name
+	Match map[string]string
+	// Version is the version captured from the file name, or the build tag
+	Version string
+	// Major is the major version of a semantic version
+	Major uint64
+	// Minor is the minor version of a semantic version
+	Minor uint64
+	// Patch is the patch version of a semantic version
+	Patch uint64
+	// Prerelease is the prerelease version of a semantic version
+	Prerelease string
+}
+
+// layoutTarget represents a file uploaded to the target path rendered from the layout.
+type layoutTarget struct {
+	// Source is the path to the file to upload
+	Source string
+	// Target is the path in Artifactory to upload the file to
+	Target string
+}
+
+// layoutFile represents a file matching an upload source.
+type layoutFile struct {
+	// Path is the path to the file
+	Path string
+	// Dir is the directory of the file relative to the matched source directory
+	Dir string
+}
+
+// validateLayout verifies the layout is properly configured and resolves for every source.
+func (u *Upload) validateLayout() error {
+	if len(u.Layout) == 0 {
+		// verify a layout is provided for the layout pattern
+		if len(u.LayoutPattern) > 0 {
+			return fmt.Errorf("no upload layout provided for layout pattern %s", u.LayoutPattern)
+		}
+
+		return nil
+	}
+
+	// verify the sources are read as files instead of regular expressions
+	if u.Regexp {
+		return fmt.Errorf("upload layout is not supported with regexp sources")
+	}
+
+	_, err := parseTemplate("layout", u.Layout)
+	if err != nil {
+		return fmt.Errorf("invalid upload layout %s provided: %w", u.Layout, err)
+	}
+
+	_, err = u.LayoutTargets(newBuildMetadata())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LayoutTargets returns the target path rendered from the layout for every file matching the sources.
+func (u *Upload) LayoutTargets(build *BuildMetadata) ([]*layoutTarget, error) {
+	var pattern *regexp.Regexp
+
+	if len(u.LayoutPattern) > 0 {
+		re, err := regexp.Compile(u.LayoutPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upload layout pattern %s provided: %w", u.LayoutPattern, err)
+		}
+
+		pattern = re
+	}
+
+	sources, err := u.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		targets []*layoutTarget
+		matched []string
+	)
+
+	for _, source := range sources {
+		files, err := u.layoutFiles(source.Pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			// skip the files matching the exclusions
+			excluded, err := matchExclusions(file.Path, u.Exclusions, clientutils.WildCardPattern, u.Recursive)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(excluded) > 0 {
+				matched = append(matched, excluded...)
+
+				continue
+			}
+
+			data, err := newLayoutTemplateData(build, file.Path, file.Dir, pattern)
+			if err != nil {
+				return nil, err
+			}
+
+			rendered, err := renderTemplate("layout", u.Layout, data)
+			if err != nil {
+				return nil, fmt.Errorf("unable to render upload layout for %s: %w", file.Path, err)
+			}
+
+			// verify the rendered layout stays within the upload path
+			rendered = strings.TrimPrefix(rendered, "/")
+			if len(rendered) == 0 || !filepath.IsLocal(filepath.FromSlash(rendered)) {
+				return nil, fmt.Errorf("invalid upload layout %q rendered for %s", rendered, file.Path)
+			}
+
+			targets = append(targets, &layoutTarget{
+				Source: file.Path,
+				Target: path.Join(u.Path, rendered),
+			})
+		}
+	}
+
+	// verify every exclusion matches the files selected by the sources
+	var unmatched []string
+
+	for _, exclusion := range u.Exclusions {
+		if !slices.Contains(matched, exclusion) {
+			unmatched = append(unmatched, exclusion)
+		}
+	}
+
+	if len(unmatched) > 0 {
+		return nil, fmt.Errorf("upload exclusions do not match any files: %s", strings.Join(unmatched, ", "))
+	}
+
+	return targets, nil
+}
+
+// layoutFiles returns every file matching the source, along with its directory relative
+// to the matched path. Directories are only walked for recursive uploads.
+func (u *Upload) layoutFiles(source string) ([]*layoutFile, error) {
+	matches, err := filepath.Glob(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload source %s provided: %w", source, err)
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files found for upload source %s", source)
+	}
+
+	var files []*layoutFile
+
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, &layoutFile{Path: match})
+
+			continue
+		}
+
+		if !u.Recursive {
+			continue
+		}
+
+		err = filepath.WalkDir(match, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			rel, err := filepath.Rel(match, filepath.Dir(file))
+			if err != nil {
+				return err
+			}
+
+			if rel == "." {
+				rel = ""
+			}
+
+			files = append(files, &layoutFile{Path: file, Dir: filepath.ToSlash(rel)})
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// newLayoutTemplateData creates the values available to the layout template for the file.
+func newLayoutTemplateData(build *BuildMetadata, file, dir string, pattern *regexp.Regexp) (*layoutTemplateData, error) {
+	base := filepath.Base(file)
+
+	data := &layoutTemplateData{
+		BuildMetadata: build,
+		File:          base,
+		Dir:           dir,
+		Match:         make(map[string]string),
+		Version:       build.Tag,
+	}
+
+	ext := filepath.Ext(base)
+
+	for _, compound := range compoundExts {
+		if strings.HasSuffix(strings.ToLower(base), compound) {
+			ext = base[len(base)-len(compound):]
+
+			break
+		}
+	}
+
+	data.Name = strings.TrimSuffix(base, ext)
+	data.Ext = strings.TrimPrefix(ext, ".")
+
+	// capture tokens from the file name using the layout pattern
+	if pattern != nil {
+		match := pattern.FindStringSubmatch(base)
+		if match == nil {
+			return nil, fmt.Errorf("file %s does not match upload layout pattern %s", file, pattern)
+		}
+
+		for i, name := range pattern.SubexpNames() {
+			if i == 0 {
+				continue
+			}
+
+			data.Match[fmt.Sprint(i)] = match[i]
+
+			if len(name) > 0 {
+				data.Match[name] = match[i]
+			}
+		}
+
+		if version, ok := data.Match["version"]; ok {
+			data.Version = version
+		}
+	}
+
+	version, err := semver.NewVersion(data.Version)
+	if err == nil {
+		data.Major = version.Major()
+		data.Minor = version.Minor()
+		data.Patch = version.Patch()
+		data.Prerelease = version.Prerelease()
+	}
+
+	return data, nil
+}
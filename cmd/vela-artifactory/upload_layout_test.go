@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_Upload_LayoutTargets(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	for _, file := range []string{"app-1.4.2.tar.gz", "app-2.0.0-rc.1.zip", "docs/guide.md", "docs/api/index.html"} {
+		err := os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), 0o755)
+		if err != nil {
+			t.Fatalf("unable to create directory: %v", err)
+		}
+
+		err = os.WriteFile(filepath.Join(dir, file), []byte(file), 0o600)
+		if err != nil {
+			t.Fatalf("unable to write file: %v", err)
+		}
+	}
+
+	build := &BuildMetadata{
+		Org:         "octocat",
+		RepoName:    "hello-world",
+		BuildNumber: "42",
+		Tag:         "v3.1.0",
+	}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		u       *Upload
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "captured version",
+			u: &Upload{
+				Path:          "generic-local",
+				Sources:       []string{filepath.Join(dir, "app-*")},
+				Layout:        "{{ .Org }}/{{ .Match.module }}/{{ .Version }}/{{ .Match.module }}-{{ .Version }}.{{ .Ext }}",
+				LayoutPattern: `^(?P<module>[a-z]+)-(?P<version>.+?)\.(tar\.gz|zip)$`,
+			},
+			want: map[string]string{
+				"app-1.4.2.tar.gz":   "generic-local/octocat/app/1.4.2/app-1.4.2.tar.gz",
+				"app-2.0.0-rc.1.zip": "generic-local/octocat/app/2.0.0-rc.1/app-2.0.0-rc.1.zip",
+			},
+		},
+		{
+			name: "semantic version fields",
+			u: &Upload{
+				Path:          "generic-local",
+				Sources:       []string{filepath.Join(dir, "app-*.zip")},
+				Layout:        "{{ index .Match \"1\" }}/{{ .Major }}.x/{{ .Prerelease }}/{{ .File }}",
+				LayoutPattern: `^([a-z]+)-(?P<version>.+)\.zip$`,
+			},
+			want: map[string]string{
+				"app-2.0.0-rc.1.zip": "generic-local/app/2.x/rc.1/app-2.0.0-rc.1.zip",
+			},
+		},
+		{
+			name: "build tag version",
+			u: &Upload{
+				Path:    "generic-local",
+				Sources: []string{filepath.Join(dir, "app-1.4.2.tar.gz")},
+				Layout:  "{{ .RepoName }}/{{ .Major }}.{{ .Minor }}/{{ .Name }}-b{{ .BuildNumber }}.{{ .Ext }}",
+			},
+			want: map[string]string{
+				"app-1.4.2.tar.gz": "generic-local/hello-world/3.1/app-1.4.2-b42.tar.gz",
+			},
+		},
+		{
+			name: "recursive directory",
+			u: &Upload{
+				Path:      "docs-local",
+				Recursive: true,
+				Sources:   []string{filepath.Join(dir, "docs")},
+				Layout:    "{{ .Version }}/{{ with .Dir }}{{ . }}/{{ end }}{{ .File }}",
+			},
+			want: map[string]string{
+				"index.html": "docs-local/v3.1.0/api/index.html",
+				"guide.md":   "docs-local/v3.1.0/guide.md",
+			},
+		},
+		{
+			name: "file does not match pattern",
+			u: &Upload{
+				Path:          "generic-local",
+				Sources:       []string{filepath.Join(dir, "app-*")},
+				Layout:        "{{ .Match.version }}/{{ .File }}",
+				LayoutPattern: `^(?P<version>\d+)\.zip$`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown capture group",
+			u: &Upload{
+				Path:          "generic-local",
+				Sources:       []string{filepath.Join(dir, "app-*.zip")},
+				Layout:        "{{ .Match.module }}/{{ .File }}",
+				LayoutPattern: `^app-(?P<version>.+)\.zip$`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "layout outside path",
+			u: &Upload{
+				Path:    "generic-local",
+				Sources: []string{filepath.Join(dir, "app-*.zip")},
+				Layout:  "../{{ .File }}",
+			},
+			wantErr: true,
+		},
+		{
+			name: "no matching files",
+			u: &Upload{
+				Path:    "generic-local",
+				Sources: []string{filepath.Join(dir, "*.jar")},
+				Layout:  "{{ .File }}",
+			},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			targets, err := test.u.LayoutTargets(build)
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("LayoutTargets should have returned err")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("LayoutTargets returned err: %v", err)
+			}
+
+			got := make(map[string]string)
+
+			for _, target := range targets {
+				got[filepath.Base(target.Source)] = target.Target
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("LayoutTargets is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Upload_Validate_Layout(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		u       *Upload
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			u:    &Upload{Path: "foo", Sources: []string{"mock/testdata/*.txt"}, Layout: "{{ .Name }}/{{ .File }}"},
+		},
+		{
+			name:    "pattern without layout",
+			u:       &Upload{Path: "foo", Sources: []string{"mock/testdata/*.txt"}, LayoutPattern: "(.*)"},
+			wantErr: true,
+		},
+		{
+			name:    "regexp sources",
+			u:       &Upload{Path: "foo", Sources: []string{"mock/testdata/(.*).txt"}, Layout: "{{ .File }}", Regexp: true},
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			u:       &Upload{Path: "foo", Sources: []string{"mock/testdata/*.txt"}, Layout: "{{ .File"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			u:       &Upload{Path: "foo", Sources: []string{"mock/testdata/*.txt"}, Layout: "{{ .File }}", LayoutPattern: "("},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.u.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Plugin_Exec_UploadWithLayout(t *testing.T) {
+	// setup types
+	var (
+		mu       sync.Mutex
+		uploaded []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		mu.Lock()
+		uploaded = append(uploaded, r.URL.Path)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "upload",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Upload: &Upload{
+			Path:          "generic-local",
+			Sources:       []string{"mock/testdata/*.txt"},
+			Layout:        "{{ .Match.name }}/{{ .Match.name }}.{{ .Ext }}",
+			LayoutPattern: `^(?P<name>[a-z]+)\.txt$`,
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	sort.Strings(uploaded)
+
+	want := []string{"/generic-local/bar/bar.txt", "/generic-local/baz/baz.txt"}
+
+	if !reflect.DeepEqual(uploaded, want) {
+		t.Errorf("Exec uploaded %v, want %v", uploaded, want)
+	}
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	yaml "github.com/ghodss/yaml"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+)
+
+// UploadSource represents an entry for the files to upload,
+// provided in either the upload sources or the upload spec.
+type UploadSource struct {
+	// Pattern is the pattern for the files to upload (provided as 'source' in the upload spec)
+	Pattern string
+	// Target is the path to upload the files to (uses the upload path if empty)
+	Target string
+	// Props are properties to set on the uploaded files (supports templates)
+	Props []*Prop
+	// Flat is a flag that enables uploading the files to the exact target path (uses the upload flat if empty)
+	Flat *bool
+	// Recursive is a flag that enables uploading sub-directories for the pattern (uses the upload recursive if empty)
+	Recursive *bool
+	// Regexp is a flag that enables reading the pattern as a regular expression (uses the upload regexp if empty)
+	Regexp *bool
+	// Explode is a flag that enables extracting an uploaded archive in the target path
+	Explode bool
+	// Exclusions are patterns for the files to exclude from the upload
+	Exclusions []string
+}
+
+// UnmarshalJSON captures the source from JSON, accepting a plain string
+// as the pattern for backwards compatibility, and the pattern as
+// 'source' for entries provided in the upload spec.
+func (s *UploadSource) UnmarshalJSON(data []byte) error {
+	var pattern string
+
+	// check if the source is provided as a plain string
+	err := json.Unmarshal(data, &pattern)
+	if err == nil {
+		*s = UploadSource{Pattern: pattern}
+
+		return nil
+	}
+
+	// the alias prevents recursively calling this method
+	type source UploadSource
+
+	raw := new(struct {
+		source
+
+		Source string
+	})
+
+	err = json.Unmarshal(data, raw)
+	if err != nil {
+		return err
+	}
+
+	// check if the pattern is provided as the source
+	if len(raw.Source) > 0 {
+		// verify the pattern is only provided once
+		if len(raw.Pattern) > 0 {
+			return fmt.Errorf("upload source %s and pattern %s are mutually exclusive", raw.Source, raw.Pattern)
+		}
+
+		raw.Pattern = raw.Source
+	}
+
+	*s = UploadSource(raw.source)
+
+	return nil
+}
+
+// structured returns true when the source configures more than the pattern.
+func (s *UploadSource) structured() bool {
+	return len(s.Target) > 0 ||
+		len(s.Props) > 0 ||
+		s.Flat != nil ||
+		s.Recursive != nil ||
+		s.Regexp != nil ||
+		s.Explode ||
+		len(s.Exclusions) > 0
+}
+
+// entries returns the upload sources, parsing the structured entries when the
+// sources are provided as a list of objects. Vela provides a list containing
+// objects as JSON, which is split on the commas by the string slice flag.
+func (u *Upload) entries() ([]*UploadSource, error) {
+	raw := strings.TrimSpace(strings.Join(u.Sources, ","))
+
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		var sources []*UploadSource
+
+		// the YAML is converted to JSON, so the sources may be provided as either
+		err := yaml.Unmarshal([]byte(raw), &sources)
+		if err == nil {
+			return sources, nil
+		}
+
+		// a plain string may be a glob pattern that starts with a character class
+		if strings.ContainsAny(raw, "{\"") {
+			return nil, fmt.Errorf("unable to unmarshal upload sources: %w", err)
+		}
+	}
+
+	sources := make([]*UploadSource, 0, len(u.Sources))
+
+	for _, pattern := range u.Sources {
+		sources = append(sources, &UploadSource{Pattern: pattern})
+	}
+
+	return sources, nil
+}
+
+// target returns the path to upload the files matching the source to.
+func (u *Upload) target(source *UploadSource) string {
+	if len(source.Target) > 0 {
+		return source.Target
+	}
+
+	return u.Path
+}
+
+// sourceOption returns the value of the option for the source,
+// or the value for the upload when the source does not provide it.
+func sourceOption(option *bool, upload bool) bool {
+	if option != nil {
+		return *option
+	}
+
+	return upload
+}
+
+// sourceParams creates the upload parameters for the files matching the source.
+func (u *Upload) sourceParams(source *UploadSource, build *BuildMetadata) (services.UploadParams, error) {
+	// create new upload parameters
+	p := services.NewUploadParams()
+
+	// apply build props
+	p.BuildProps = u.BuildProps
+
+	// add upload configuration to upload parameters
+	p.CommonParams = &utils.CommonParams{
+		Exclusions:  slices.Concat(u.Exclusions, source.Exclusions),
+		IncludeDirs: u.IncludeDirs,
+		Pattern:     source.Pattern,
+		Recursive:   sourceOption(source.Recursive, u.Recursive),
+		Regexp:      sourceOption(source.Regexp, u.Regexp),
+		Target:      u.target(source),
+	}
+
+	// apply properties for the source
+	props, err := sourceProps(source, build)
+	if err != nil {
+		return p, err
+	}
+
+	p.TargetProps = props
+
+	// upload to exact target path
+	p.Flat = sourceOption(source.Flat, u.Flat)
+
+	// extract an uploaded archive in the target path
+	p.ExplodeArchive = source.Explode
+
+	return p, nil
+}
+
+// sourcesParams creates the upload parameters for the files matching each of the sources.
+func (u *Upload) sourcesParams(sources []*UploadSource, build *BuildMetadata) ([]services.UploadParams, error) {
+	params := make([]services.UploadParams, 0, len(sources))
+
+	for _, source := range sources {
+		p, err := u.sourceParams(source, build)
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, p)
+	}
+
+	return params, nil
+}
+
+// sourceProps renders the properties set on the files matching the source.
+func sourceProps(source *UploadSource, build *BuildMetadata) (*utils.Properties, error) {
+	if len(source.Props) == 0 {
+		return nil, nil
+	}
+
+	// render each property using the build information
+	props, err := renderProps(source.Props, build)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := utils.ParseProperties(strings.Join(props, ";"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse upload props for source %s: %w", source.Pattern, err)
+	}
+
+	return parsed, nil
+}
+
+// validateSource verifies the upload source is properly configured.
+func (u *Upload) validateSource(i int, source *UploadSource) error {
+	// verify pattern is provided
+	if len(source.Pattern) == 0 {
+		return fmt.Errorf("no pattern provided for upload source %d", i+1)
+	}
+
+	// verify target or path is provided
+	if len(u.target(source)) == 0 {
+		return fmt.Errorf("no upload path provided for source %s", source.Pattern)
+	}
+
+	// verify exclusions are not empty
+	if slices.Contains(source.Exclusions, "") {
+		return fmt.Errorf("empty exclusion provided for upload source %s", source.Pattern)
+	}
+
+	// verify the properties and their templates are valid
+	err := validatePropTemplates(source.Props)
+	if err != nil {
+		return fmt.Errorf("invalid upload prop provided for source %s: %w", source.Pattern, err)
+	}
+
+	err = validateSetMode(source.Props)
+	if err != nil {
+		return fmt.Errorf("invalid upload prop provided for source %s: %w", source.Pattern, err)
+	}
+
+	return nil
+}
+
+// validateSources verifies every upload source is properly configured.
+func (u *Upload) validateSources() error {
+	sources, err := u.entries()
+	if err != nil {
+		return err
+	}
+
+	// verify sources are provided
+	if len(sources) == 0 {
+		return fmt.Errorf("no upload sources provided")
+	}
+
+	for i, source := range sources {
+		err = u.validateSource(i, source)
+		if err != nil {
+			return err
+		}
+
+		// verify the layout is only used with the pattern
+		if len(u.Layout) > 0 && source.structured() {
+			return fmt.Errorf("upload layout is not supported with structured source %s", source.Pattern)
+		}
+	}
+
+	// the exclusions are applied to the files rendered from the layout instead
+	if len(u.Layout) > 0 {
+		return nil
+	}
+
+	params, err := u.sourcesParams(sources, newBuildMetadata())
+	if err != nil {
+		return err
+	}
+
+	// verify every exclusion matches the files selected by the sources
+	return u.validateExclusions(params)
+}
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+func TestArtifactory_Upload_entries(t *testing.T) {
+	// setup types
+	flat := false
+	recursive := true
+
+	// setup tests
+	tests := []struct {
+		name    string
+		sources []string
+		want    []*UploadSource
+		wantErr bool
+	}{
+		{
+			name:    "plain",
+			sources: []string{"dist/*.tgz", "docs/**"},
+			want:    []*UploadSource{{Pattern: "dist/*.tgz"}, {Pattern: "docs/**"}},
+		},
+		{
+			name:    "character class",
+			sources: []string{"[ab]*.txt"},
+			want:    []*UploadSource{{Pattern: "[ab]*.txt"}},
+		},
+		{
+			// the JSON provided by Vela is split on the commas by the string slice flag
+			name:    "structured",
+			sources: strings.Split(`["dist/*.tgz",{"pattern":"docs/**","target":"docs-local/","flat":false,"exclusions":["*.map"],"props":[{"name":"os","value":"linux"}]}]`, ","),
+			want: []*UploadSource{
+				{Pattern: "dist/*.tgz"},
+				{
+					Pattern:    "docs/**",
+					Target:     "docs-local/",
+					Flat:       &flat,
+					Exclusions: []string{"*.map"},
+					Props:      []*Prop{{Name: "os", Value: "linux"}},
+				},
+			},
+		},
+		{
+			name:    "source",
+			sources: strings.Split(`[{"source":"dist/*.zip","recursive":true,"regexp":false,"explode":true}]`, ","),
+			want: []*UploadSource{
+				{Pattern: "dist/*.zip", Recursive: &recursive, Regexp: &flat, Explode: true},
+			},
+		},
+		{
+			name:    "source and pattern",
+			sources: strings.Split(`[{"source":"dist/*.zip","pattern":"dist/*.tgz"}]`, ","),
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			sources: []string{`[{"pattern":["docs/**"]}]`},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			u := &Upload{Sources: test.sources}
+
+			got, err := u.entries()
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("entries should have returned err")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("entries returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("entries is %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Plugin_Exec_UploadWithStructuredSources(t *testing.T) {
+	// setup types
+	t.Setenv("VELA_REPO_FULL_NAME", "octocat")
+
+	var (
+		mu       sync.Mutex
+		uploaded []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		mu.Lock()
+		uploaded = append(uploaded, r.URL.Path)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer s.Close()
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "upload",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Upload: &Upload{
+			Flat: true,
+			Path: "foo/bar/",
+			Sources: strings.Split(`[
+  "mock/testdata/bar.txt",
+  {"pattern": "mock/testdata/*.txt", "target": "docs-local/app/", "exclusions": ["*bar*"], "props": [{"name": "repo", "value": "{{ .Repo }}"}]},
+  {"pattern": "mock/testdata/baz.txt", "target": "generic-local/", "flat": false}
+]`, ","),
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	sort.Strings(uploaded)
+
+	want := []string{
+		"/docs-local/app/baz.txt;repo=octocat",
+		"/foo/bar/bar.txt",
+		"/generic-local/mock/testdata/baz.txt",
+	}
+
+	if !reflect.DeepEqual(uploaded, want) {
+		t.Errorf("Exec uploaded %v, want %v", uploaded, want)
+	}
+}
+
+func TestArtifactory_Upload_sourceParams(t *testing.T) {
+	// setup types
+	enabled := true
+	disabled := false
+
+	u := &Upload{
+		Path:       "foo/bar/",
+		Flat:       true,
+		Recursive:  true,
+		Exclusions: []string{"*.map"},
+	}
+
+	// setup tests
+	tests := []struct {
+		name          string
+		source        *UploadSource
+		wantFlat      bool
+		wantRecursive bool
+		wantRegexp    bool
+		wantExplode   bool
+		wantTarget    string
+		wantExclude   []string
+	}{
+		{
+			name:          "upload options",
+			source:        &UploadSource{Pattern: "dist/*.zip"},
+			wantFlat:      true,
+			wantRecursive: true,
+			wantTarget:    "foo/bar/",
+			wantExclude:   []string{"*.map"},
+		},
+		{
+			name: "source options",
+			source: &UploadSource{
+				Pattern:    "dist/(.*).zip",
+				Target:     "generic-local/{1}/",
+				Flat:       &disabled,
+				Recursive:  &disabled,
+				Regexp:     &enabled,
+				Explode:    true,
+				Exclusions: []string{"*.tmp"},
+			},
+			wantRegexp:  true,
+			wantExplode: true,
+			wantTarget:  "generic-local/{1}/",
+			wantExclude: []string{"*.map", "*.tmp"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := u.sourceParams(test.source, newBuildMetadata())
+			if err != nil {
+				t.Fatalf("sourceParams returned err: %v", err)
+			}
+
+			if got.Flat != test.wantFlat {
+				t.Errorf("sourceParams flat is %v, want %v", got.Flat, test.wantFlat)
+			}
+
+			if got.IsRecursive() != test.wantRecursive {
+				t.Errorf("sourceParams recursive is %v, want %v", got.IsRecursive(), test.wantRecursive)
+			}
+
+			if got.Regexp != test.wantRegexp {
+				t.Errorf("sourceParams regexp is %v, want %v", got.Regexp, test.wantRegexp)
+			}
+
+			if got.IsExplodeArchive() != test.wantExplode {
+				t.Errorf("sourceParams explode is %v, want %v", got.IsExplodeArchive(), test.wantExplode)
+			}
+
+			if got.GetTarget() != test.wantTarget {
+				t.Errorf("sourceParams target is %s, want %s", got.GetTarget(), test.wantTarget)
+			}
+
+			if !reflect.DeepEqual(got.GetExclusions(), test.wantExclude) {
+				t.Errorf("sourceParams exclusions is %v, want %v", got.GetExclusions(), test.wantExclude)
+			}
+		})
+	}
+}
+
+func TestArtifactory_Upload_Validate_Sources(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		upload  *Upload
+		wantErr bool
+	}{
+		{
+			name:   "plain",
+			upload: &Upload{Path: "foo/bar/", Sources: []string{"mock/testdata/bar.txt"}},
+		},
+		{
+			name:   "target without path",
+			upload: &Upload{Sources: []string{`[{"pattern":"mock/testdata/bar.txt","target":"foo/bar/"}]`}},
+		},
+		{
+			name:    "no target or path",
+			upload:  &Upload{Sources: []string{`["mock/testdata/baz.txt"`, `{"pattern":"mock/testdata/bar.txt"`, `"target":"foo/bar/"}]`}},
+			wantErr: true,
+		},
+		{
+			name:    "no pattern",
+			upload:  &Upload{Path: "foo/bar/", Sources: []string{`[{"target":"foo/bar/"}]`}},
+			wantErr: true,
+		},
+		{
+			name:    "empty exclusion",
+			upload:  &Upload{Path: "foo/bar/", Sources: []string{`[{"pattern":"mock/testdata/bar.txt"`, `"exclusions":[""]}]`}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid prop",
+			upload:  &Upload{Path: "foo/bar/", Sources: []string{`[{"pattern":"mock/testdata/bar.txt"`, `"props":[{"name":"os"}]}]`}},
+			wantErr: true,
+		},
+		{
+			name:    "prop mode",
+			upload:  &Upload{Path: "foo/bar/", Sources: []string{`[{"pattern":"mock/testdata/bar.txt"`, `"props":[{"name":"os","value":"linux","mode":"append"}]}]`}},
+			wantErr: true,
+		},
+		{
+			name:    "layout",
+			upload:  &Upload{Path: "foo/bar/", Layout: "{{ .File }}", Sources: []string{`[{"pattern":"mock/testdata/bar.txt"`, `"target":"foo/baz/"}]`}},
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.upload.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
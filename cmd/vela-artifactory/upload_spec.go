@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	json "github.com/ghodss/yaml"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/sirupsen/logrus"
+)
+
+// UploadSpec represents a file in the workspace listing the files to upload.
+type UploadSpec struct {
+	// Files are the entries for the files to upload
+	Files []*UploadSource
+}
+
+// readSpec reads and parses the upload spec from the workspace.
+func (u *Upload) readSpec() (*UploadSpec, error) {
+	data, err := os.ReadFile(u.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read upload spec: %w", err)
+	}
+
+	spec := new(UploadSpec)
+
+	// the YAML is converted to JSON, so the spec may be provided as either
+	err = json.Unmarshal(data, spec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal upload spec %s: %w", u.Spec, err)
+	}
+
+	return spec, nil
+}
+
+// validateSpec verifies the upload spec is properly configured,
+// and that every source matches at least one file in the workspace.
+func (u *Upload) validateSpec() error {
+	// verify the spec is not combined with inline sources
+	if len(u.Sources) > 0 {
+		return fmt.Errorf("upload spec is mutually exclusive with sources")
+	}
+
+	// verify the spec is not combined with a layout
+	if len(u.Layout) > 0 {
+		return fmt.Errorf("upload layout is not supported with a spec")
+	}
+
+	spec, err := u.readSpec()
+	if err != nil {
+		return err
+	}
+
+	// verify files are provided
+	if len(spec.Files) == 0 {
+		return fmt.Errorf("no files provided in upload spec %s", u.Spec)
+	}
+
+	for i, file := range spec.Files {
+		err = u.validateSource(i, file)
+		if err != nil {
+			return fmt.Errorf("invalid upload spec provided: %w", err)
+		}
+	}
+
+	u.Files = spec.Files
+
+	params, err := u.sourcesParams(u.Files, newBuildMetadata())
+	if err != nil {
+		return err
+	}
+
+	// verify every source matches files the same way as the client library,
+	// so the recursive flag and ** patterns are applied as they are on upload
+	for _, p := range params {
+		plan, err := planUpload(p)
+		if err != nil {
+			return err
+		}
+
+		if len(plan.Files) == 0 && len(plan.Excluded) == 0 {
+			return fmt.Errorf("no files found for upload spec source %s", p.GetPattern())
+		}
+	}
+
+	// verify every exclusion matches the files selected by the spec
+	return u.validateExclusions(params)
+}
+
+// execSpec uploads the files for every entry in the upload spec.
+func (u *Upload) execSpec(cli artifactory.ArtifactoryServicesManager, logger *logrus.Entry) error {
+	start := time.Now()
+
+	params, err := u.sourcesParams(u.Files, newBuildMetadata())
+	if err != nil {
+		return err
+	}
+
+	for _, p := range params {
+		entry := logger.WithField("artifact", p.GetPattern())
+
+		// list the files selected for the entry
+		if u.DryRun {
+			entry.Infof("  [dry run] %s -> %s", p.GetPattern(), p.GetTarget())
+
+			err = logPlan(entry, p)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		entry.Infof("  [spec] %s -> %s", p.GetPattern(), p.GetTarget())
+	}
+
+	// send API call to upload artifacts in Artifactory
+	totalUploaded, totalFailed, err := cli.UploadFiles(artifactory.UploadServiceOptions{FailFast: true}, params...)
+	if err != nil {
+		return err
+	}
+
+	if totalFailed > 0 {
+		return fmt.Errorf("unable to upload %d artifact(s)", totalFailed)
+	}
+
+	withDuration(logger, start).WithFields(logrus.Fields{
+		"success": totalUploaded,
+		"failed":  totalFailed,
+	}).Infof("Uploaded %d artifact(s) using spec %s", totalUploaded, u.Spec)
+
+	return nil
+}
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/go-vela/vela-artifactory/cmd/vela-artifactory/mock"
+)
+
+// writeSpec writes the upload spec to a file in a temporary directory.
+func writeSpec(t *testing.T, name, spec string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), name)
+
+	err := os.WriteFile(file, []byte(spec), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	return file
+}
+
+func TestArtifactory_Plugin_Exec_UploadWithSpec(t *testing.T) {
+	// setup types
+	t.Setenv("VELA_REPO_FULL_NAME", "octocat")
+
+	var (
+		mu       sync.Mutex
+		uploaded []string
+	)
+
+	handler := mock.Handlers()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		mu.Lock()
+		uploaded = append(uploaded, r.URL.Path+" explode="+r.Header.Get("X-Explode-Archive"))
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer s.Close()
+
+	spec := writeSpec(t, "upload.yml", `
+files:
+  - source: mock/testdata/bar.txt
+    target: generic-local/app/
+    flat: true
+    props:
+      - name: os
+        values:
+          - linux
+          - darwin
+  - source: mock/testdata/baz.txt
+    flat: true
+    explode: true
+    props:
+      - name: repo
+        value: "{{ .Repo }}"
+`)
+
+	p := &Plugin{
+		Config: &Config{
+			Action:   "upload",
+			URL:      s.URL,
+			Username: mock.Username,
+			Password: mock.Password,
+			Client: &Client{
+				Retries:            3,
+				RetryWaitMilliSecs: 1,
+			},
+		},
+		Upload: &Upload{
+			Path: "docs-local/",
+			Spec: spec,
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Fatalf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	sort.Strings(uploaded)
+
+	want := []string{
+		"/docs-local/baz.txt;repo=octocat explode=true",
+		"/generic-local/app/bar.txt;os=linux;os=darwin explode=",
+	}
+
+	if !reflect.DeepEqual(uploaded, want) {
+		t.Errorf("Exec uploaded %v, want %v", uploaded, want)
+	}
+}
+
+func TestArtifactory_Upload_Validate_Spec(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		upload  *Upload
+		spec    string
+		wantErr bool
+	}{
+		{
+			name:   "yaml",
+			upload: &Upload{Path: "generic-local/"},
+			spec:   "files:\n  - source: mock/testdata/*.txt\n",
+		},
+		{
+			name:   "json",
+			upload: &Upload{},
+			spec:   `{"files": [{"source": "mock/testdata/(*).txt", "target": "generic-local/{1}/"}]}`,
+		},
+		{
+			name:   "regexp",
+			upload: &Upload{},
+			spec:   "files:\n  - source: mock/testdata/(.*)\\.txt\n    target: generic-local/{1}/\n    regexp: true\n",
+		},
+		{
+			name:   "recursive",
+			upload: &Upload{Path: "generic-local/"},
+			spec:   "files:\n  - source: mock/**/bar.txt\n    recursive: true\n",
+		},
+		{
+			name:    "not recursive",
+			upload:  &Upload{Path: "generic-local/"},
+			spec:    "files:\n  - source: mock/**/bar.txt\n",
+			wantErr: true,
+		},
+		{
+			name:   "wildcard in sub-directory",
+			upload: &Upload{Path: "generic-local/"},
+			spec:   "files:\n  - source: mock/*.txt\n    recursive: true\n",
+		},
+		{
+			name:   "exclusions",
+			upload: &Upload{Path: "generic-local/"},
+			spec:   "files:\n  - source: mock/testdata/*.txt\n    exclusions:\n      - \"*baz*\"\n",
+		},
+		{
+			name:    "unmatched exclusions",
+			upload:  &Upload{Path: "generic-local/"},
+			spec:    "files:\n  - source: mock/testdata/*.txt\n    exclusions:\n      - \"*.jar\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty exclusion",
+			upload:  &Upload{Path: "generic-local/"},
+			spec:    "files:\n  - source: mock/testdata/*.txt\n    exclusions:\n      - \"\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "no files",
+			upload:  &Upload{Path: "generic-local/"},
+			spec:    "files: []\n",
+			wantErr: true,
+		},
+		{
+			name:    "no source",
+			upload:  &Upload{Path: "generic-local/"},
+			spec:    "files:\n  - target: generic-local/\n",
+			wantErr: true,
+		},
+		{
+			name:    "no target",
+			upload:  &Upload{},
+			spec:    "files:\n  - source: mock/testdata/*.txt\n",
+			wantErr: true,
+		},
+		{
+			name:    "no matching files",
+			upload:  &Upload{Path: "generic-local/"},
+			spec:    "files:\n  - source: mock/testdata/*.jar\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid prop",
+			upload:  &Upload{Path: "generic-local/"},
+			spec:    "files:\n  - source: mock/testdata/*.txt\n    props:\n      - name: os\n",
+			wantErr: true,
+		},
+		{
+			name:    "prop mode",
+			upload:  &Upload{Path: "generic-local/"},
+			spec:    "files:\n  - source: mock/testdata/*.txt\n    props:\n      - name: os\n        value: linux\n        mode: append\n",
+			wantErr: true,
+		},
+		{
+			name:    "sources",
+			upload:  &Upload{Path: "generic-local/", Sources: []string{"mock/testdata/bar.txt"}},
+			spec:    "files:\n  - source: mock/testdata/*.txt\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid spec",
+			upload:  &Upload{Path: "generic-local/"},
+			spec:    "files: {",
+			wantErr: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.upload.Spec = writeSpec(t, "upload.yml", test.spec)
+
+			err := test.upload.Validate()
+
+			if test.wantErr && err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			if !test.wantErr && err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+
+	// verify a missing spec returns an error
+	err := (&Upload{Path: "generic-local/", Spec: "missing.yml"}).Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
@@ -142,6 +142,79 @@ func TestArtifactory_Upload_Exec_Error(t *testing.T) {
 	}
 }
 
+func TestArtifactory_Upload_Exec_Failed(t *testing.T) {
+	// setup types
+	spec := writeSpec(t, "upload.yml", `
+files:
+  - source: mock/testdata/bar.txt
+    target: foo/bar/
+`)
+
+	handler := mock.Handlers()
+
+	// reject every upload sent to the mock server
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	config := &Config{
+		Action:   "upload",
+		URL:      s.URL,
+		Username: mock.Username,
+		Password: mock.Password,
+		Client: &Client{
+			Retries:            0,
+			RetryWaitMilliSecs: 1,
+		},
+	}
+
+	cli, err := config.New()
+	if err != nil {
+		t.Fatalf("Unable to create Artifactory client: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name   string
+		upload *Upload
+	}{
+		{
+			name:   "sources",
+			upload: &Upload{Flat: true, Path: "foo/bar/", Sources: []string{"mock/testdata/bar.txt"}},
+		},
+		{
+			name:   "layout",
+			upload: &Upload{Path: "foo/bar/", Sources: []string{"mock/testdata/bar.txt"}, Layout: "{{ .File }}"},
+		},
+		{
+			name:   "spec",
+			upload: &Upload{Spec: spec},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.upload.Validate()
+			if err != nil {
+				t.Fatalf("Validate returned err: %v", err)
+			}
+
+			err = test.upload.Exec(*cli)
+			if err == nil {
+				t.Errorf("Exec should have returned err")
+			}
+		})
+	}
+}
+
 func TestArtifactory_Upload_Validate(t *testing.T) {
 	// setup types
 	u := &Upload{
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// BuildMetadata represents the Vela build information available to templates.
+//
+// https://go-vela.github.io/docs/reference/environment/variables/
+type BuildMetadata struct {
+	// Author is the author of the build (VELA_BUILD_AUTHOR)
+	Author string
+	// Branch is the branch for the build (VELA_BUILD_BRANCH)
+	Branch string
+	// BuildLink is the link to the build in the Vela UI (VELA_BUILD_LINK)
+	BuildLink string
+	// BuildNumber is the number of the build (VELA_BUILD_NUMBER)
+	BuildNumber string
+	// Commit is the commit SHA for the build (VELA_BUILD_COMMIT)
+	Commit string
+	// CommitShort is the first 8 characters of the commit SHA for the build
+	CommitShort string
+	// Created is the time the build was created (VELA_BUILD_CREATED)
+	Created time.Time
+	// Event is the event that triggered the build (VELA_BUILD_EVENT)
+	Event string
+	// Org is the organization of the repository for the build (VELA_REPO_ORG)
+	Org string
+	// Ref is the git reference for the build (VELA_BUILD_REF)
+	Ref string
+	// Repo is the full name of the repository for the build (VELA_REPO_FULL_NAME)
+	Repo string
+	// RepoLink is the link to the repository for the build (VELA_REPO_LINK)
+	RepoLink string
+	// RepoName is the name of the repository for the build (VELA_REPO_NAME)
+	RepoName string
+	// Tag is the git tag for the build (VELA_BUILD_TAG)
+	Tag string
+}
+
+// newBuildMetadata creates the Vela build information from the environment.
+func newBuildMetadata() *BuildMetadata {
+	b := &BuildMetadata{
+		Author:      os.Getenv("VELA_BUILD_AUTHOR"),
+		Branch:      os.Getenv("VELA_BUILD_BRANCH"),
+		BuildLink:   os.Getenv("VELA_BUILD_LINK"),
+		BuildNumber: os.Getenv("VELA_BUILD_NUMBER"),
+		Commit:      os.Getenv("VELA_BUILD_COMMIT"),
+		Event:       os.Getenv("VELA_BUILD_EVENT"),
+		Org:         os.Getenv("VELA_REPO_ORG"),
+		Ref:         os.Getenv("VELA_BUILD_REF"),
+		Repo:        os.Getenv("VELA_REPO_FULL_NAME"),
+		RepoLink:    os.Getenv("VELA_REPO_LINK"),
+		RepoName:    os.Getenv("VELA_REPO_NAME"),
+		Tag:         os.Getenv("VELA_BUILD_TAG"),
+	}
+
+	b.CommitShort = b.Commit
+	if len(b.CommitShort) > 8 {
+		b.CommitShort = b.CommitShort[:8]
+	}
+
+	// the build created time is provided as a unix timestamp
+	created, err := strconv.ParseInt(os.Getenv("VELA_BUILD_CREATED"), 10, 64)
+	if err == nil {
+		b.Created = time.Unix(created, 0).UTC()
+	}
+
+	return b
+}
+
+// templateFuncs represents the functions available to templates.
+var templateFuncs = template.FuncMap{
+	"now":     func() time.Time { return time.Now().UTC() },
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"trim":    strings.TrimSpace,
+	"replace": strings.ReplaceAll,
+}
+
+// parseTemplate parses the text as a template with the functions available to templates.
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// renderTemplate executes the text as a template with the provided data.
+func renderTemplate(name, text string, data any) (string, error) {
+	// avoid parsing text that does not contain a template action
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+
+	err = tmpl.Execute(buf, data)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}